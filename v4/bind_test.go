@@ -0,0 +1,288 @@
+package raptor_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/errs"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type bindPayload struct {
+	Name string `json:"name" xml:"name" form:"name"`
+	Age  int    `json:"age" xml:"age" form:"age"`
+}
+
+type BindController struct {
+	raptor.Controller
+}
+
+func (c *BindController) Echo(ctx *raptor.Context) error {
+	var p bindPayload
+	if err := ctx.Bind(&p); err != nil {
+		return err
+	}
+	return ctx.Data(p)
+}
+
+func newBindApp() *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&BindController{}}},
+		router.CollectRoutes(router.Post("/echo", "Bind.Echo")),
+	)
+}
+
+func decodeBindPayload(t *testing.T, body string) bindPayload {
+	t.Helper()
+	var p bindPayload
+	if err := json.Unmarshal([]byte(body), &p); err != nil {
+		t.Fatalf("decode response %q: %v", body, err)
+	}
+	return p
+}
+
+func TestBindSelectsDecoderByContentType(t *testing.T) {
+	app := newBindApp()
+
+	cases := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/json", `{"name":"rex","age":7}`},
+		{"application/xml; charset=UTF-8", `<bindPayload><name>rex</name><age>7</age></bindPayload>`},
+		{"application/x-www-form-urlencoded", "name=rex&age=7"},
+	}
+	for _, tc := range cases {
+		rec := app.TestPost("/echo", strings.NewReader(tc.body), raptor.WithHeader("Content-Type", tc.contentType))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: got %d, want 200 (%s)", tc.contentType, rec.Code, rec.Body.String())
+		}
+		if got := decodeBindPayload(t, rec.Body.String()); got != (bindPayload{Name: "rex", Age: 7}) {
+			t.Fatalf("%s: got %+v", tc.contentType, got)
+		}
+	}
+}
+
+func (c *BindController) FormParams(ctx *raptor.Context) error {
+	var p bindPayload
+	if err := ctx.BindParams(&p); err != nil {
+		return err
+	}
+	return ctx.Data(p)
+}
+
+func TestBindFormBodyErrors(t *testing.T) {
+	app := raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&BindController{}}},
+		router.CollectRoutes(
+			router.Post("/echo", "Bind.Echo"),
+			router.Post("/params", "Bind.FormParams"),
+		),
+		raptor.WithConfig(&config.Config{ServerConfig: config.ServerConfig{MaxBodyBytes: 16}}),
+	)
+
+	form := raptor.WithHeader("Content-Type", "application/x-www-form-urlencoded")
+	for _, path := range []string{"/echo", "/params"} {
+		if rec := app.TestPost(path, strings.NewReader("name="+strings.Repeat("x", 100)), form); rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s oversized form: got %d %s, want 413", path, rec.Code, rec.Body.String())
+		}
+		if rec := app.TestPost(path, strings.NewReader("name=ok&x=%zz"), form); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s malformed form: got %d %s, want 400", path, rec.Code, rec.Body.String())
+		}
+		if rec := app.TestPost(path, strings.NewReader("name=ok&age=1"), form); rec.Code != http.StatusOK {
+			t.Errorf("%s valid form: got %d %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestBindUnsupportedMediaType(t *testing.T) {
+	app := newBindApp()
+
+	rec := app.TestPost("/echo", strings.NewReader("name: rex"), raptor.WithHeader("Content-Type", "application/yaml"))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("got %d, want 415", rec.Code)
+	}
+}
+
+func TestBindMalformedBodyIsBadRequest(t *testing.T) {
+	app := newBindApp()
+
+	rec := app.TestPost("/echo", strings.NewReader(`{"name":`))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want 400", rec.Code)
+	}
+}
+
+func TestBindCustomDecoder(t *testing.T) {
+	app := newBindApp()
+	app.Core.RegisterDecoder("Application/X-Custom", func(r *http.Request, v any) error {
+		*v.(*bindPayload) = bindPayload{Name: "custom", Age: 1}
+		return nil
+	})
+
+	rec := app.TestPost("/echo", strings.NewReader("ignored"), raptor.WithHeader("Content-Type", "application/x-custom"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", rec.Code)
+	}
+	if got := decodeBindPayload(t, rec.Body.String()); got.Name != "custom" {
+		t.Fatalf("custom decoder not used: %+v", got)
+	}
+}
+
+type paramsPayload struct {
+	ID      int        `path:"id"`
+	Page    *int       `query:"page"`
+	Tags    []string   `query:"tag"`
+	Active  bool       `query:"active"`
+	Since   time.Time  `query:"since" layout:"2006-01-02"`
+	Tenant  string     `header:"X-Tenant"`
+	Session string     `cookie:"session"`
+	Missing *time.Time `query:"missing"`
+}
+
+func (c *BindController) Params(ctx *raptor.Context) error {
+	var p paramsPayload
+	if err := ctx.BindParams(&p); err != nil {
+		return err
+	}
+	return ctx.Data(p)
+}
+
+func newParamsApp() *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&BindController{}}},
+		router.CollectRoutes(router.Get("/things/{id}", "Bind.Params")),
+	)
+}
+
+func TestBindParamsFromAllSources(t *testing.T) {
+	app := newParamsApp()
+
+	rec := app.TestGet("/things/42?page=3&tag=a&tag=b&active=true&since=2024-05-01",
+		raptor.WithHeader("X-Tenant", "acme"),
+		raptor.WithHeader("Cookie", "session=s3cr3t"),
+	)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200 (%s)", rec.Code, rec.Body.String())
+	}
+	var got paramsPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.ID != 42 || got.Page == nil || *got.Page != 3 || !got.Active || got.Tenant != "acme" || got.Session != "s3cr3t" {
+		t.Fatalf("scalar fields not bound: %+v", got)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "a" || got.Tags[1] != "b" {
+		t.Fatalf("slice field not bound: %v", got.Tags)
+	}
+	if !got.Since.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("time field not bound with layout: %v", got.Since)
+	}
+	if got.Missing != nil {
+		t.Fatalf("absent parameters must leave fields untouched: %v", got.Missing)
+	}
+}
+
+func TestBindParamsConversionErrorsAre400WithFieldDetails(t *testing.T) {
+	app := newParamsApp()
+
+	rec := app.TestGet("/things/abc?page=x&active=maybe")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want 400", rec.Code)
+	}
+	var body struct {
+		Attrs map[string]string `json:"attrs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, field := range []string{"id", "page", "active"} {
+		if body.Attrs[field] == "" {
+			t.Fatalf("attrs should describe %q: %v", field, body.Attrs)
+		}
+	}
+}
+
+func (c *BindController) Validated(ctx *raptor.Context) error {
+	var p bindPayload
+	if err := ctx.BindAndValidate(&p); err != nil {
+		return err
+	}
+	return ctx.Data(p)
+}
+
+func newValidatedApp(opts ...raptor.RaptorOption) *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&BindController{}}},
+		router.CollectRoutes(router.Post("/validated", "Bind.Validated")),
+		opts...,
+	)
+}
+
+func TestBindAndValidateReturns422WithFieldMessages(t *testing.T) {
+	app := newValidatedApp(raptor.WithValidator(raptor.ValidatorFunc(func(v any) error {
+		p := v.(*bindPayload)
+		problems := raptor.ValidationErrors{}
+		if p.Name == "" {
+			problems.Add("name", "is required")
+		}
+		if p.Age < 0 {
+			problems.Add("age", "must be positive")
+		}
+		if len(problems) > 0 {
+			return problems
+		}
+		return nil
+	})))
+
+	rec := app.TestPost("/validated", strings.NewReader(`{"age":-1}`))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got %d, want 422", rec.Code)
+	}
+	var body struct {
+		Attrs map[string][]string `json:"attrs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Attrs["name"]) != 1 || len(body.Attrs["age"]) != 1 {
+		t.Fatalf("attrs should map each field to its messages: %v", body.Attrs)
+	}
+
+	rec = app.TestPost("/validated", strings.NewReader(`{"name":"rex","age":7}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("valid payload: got %d, want 200", rec.Code)
+	}
+}
+
+func TestValidateHidesPlainValidatorErrors(t *testing.T) {
+	app := newValidatedApp(raptor.WithValidator(raptor.ValidatorFunc(func(v any) error {
+		return errors.New("Key: 'bindPayload.Name' Error:Field validation for 'Name' failed on the 'required' tag")
+	})))
+
+	rec := app.TestPost("/validated", strings.NewReader(`{}`))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got %d, want 422", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "Validation failed") || strings.Contains(body, "bindPayload") {
+		t.Fatalf("body should carry a fixed message, not the validator's text: %s", body)
+	}
+}
+
+func TestValidateWithoutValidatorReturnsSentinel(t *testing.T) {
+	app := newValidatedApp()
+
+	rec := app.TestPost("/validated", strings.NewReader(`{"name":"rex"}`))
+	if rec.Code != errs.ErrValidatorNotRegistered.Code {
+		t.Fatalf("got %d, want %d", rec.Code, errs.ErrValidatorNotRegistered.Code)
+	}
+	if !strings.Contains(rec.Body.String(), errs.ErrValidatorNotRegistered.Message) {
+		t.Fatalf("body should carry the sentinel message: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,164 @@
+package raptor_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type PeersController struct {
+	raptor.Controller
+}
+
+func (pc *PeersController) Whoami(c *raptor.Context) error {
+	cert := c.ClientCert()
+	if cert == nil {
+		return c.Data(map[string]any{})
+	}
+	return c.Data(map[string]any{
+		"cn":     cert.CommonName,
+		"spiffe": cert.SPIFFEID,
+		"sans":   cert.SANs(),
+	})
+}
+
+func peerCert(commonName string, dnsNames []string, uris ...string) *x509.Certificate {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: commonName, Organization: []string{"Acme"}},
+		DNSNames: dnsNames,
+	}
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil {
+			panic(err)
+		}
+		cert.URIs = append(cert.URIs, u)
+	}
+	return cert
+}
+
+func newPeersApp(routes router.Routes) *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&PeersController{}}},
+		routes,
+	)
+}
+
+func TestContextClientCert(t *testing.T) {
+	app := newPeersApp(router.CollectRoutes(router.Get("/whoami", "Peers.Whoami")))
+
+	cert := peerCert("checkout", []string{"checkout.internal"}, "spiffe://example.org/ns/prod/sa/checkout")
+	rec := app.TestGet("/whoami", raptor.WithClientCert(cert))
+	var body struct {
+		CN     string   `json:"cn"`
+		SPIFFE string   `json:"spiffe"`
+		SANs   []string `json:"sans"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+	if body.CN != "checkout" || body.SPIFFE != "spiffe://example.org/ns/prod/sa/checkout" {
+		t.Fatalf("got %+v", body)
+	}
+	if len(body.SANs) != 2 || body.SANs[0] != "checkout.internal" {
+		t.Fatalf("SANs: got %v", body.SANs)
+	}
+
+	if rec := app.TestGet("/whoami"); rec.Body.String() != "{}" {
+		t.Fatalf("plain request should have no client cert: %s", rec.Body.String())
+	}
+}
+
+func TestRequireClientCert(t *testing.T) {
+	app := newPeersApp(router.CollectRoutes(
+		router.Get("/open", "Peers.Whoami"),
+		router.Group("/internal", router.RequireClientCert(nil, nil),
+			router.Get("/any", "Peers.Whoami"),
+			router.Get("/prod", "Peers.Whoami").With(router.RequireClientCert(
+				[]string{"admin"},
+				[]string{"spiffe://example.org/ns/prod/sa/*"},
+			)),
+		),
+	))
+
+	prod := peerCert("checkout", nil, "spiffe://example.org/ns/prod/sa/checkout")
+	staging := peerCert("checkout", nil, "spiffe://example.org/ns/staging/sa/checkout")
+	admin := peerCert("admin", nil)
+
+	for _, tc := range []struct {
+		path string
+		opts []raptor.TestRequestOption
+		want int
+	}{
+		{"/open", nil, http.StatusOK},
+		{"/internal/any", nil, http.StatusUnauthorized},
+		{"/internal/any", []raptor.TestRequestOption{raptor.WithClientCert(staging)}, http.StatusOK},
+		{"/internal/prod", []raptor.TestRequestOption{raptor.WithClientCert(prod)}, http.StatusOK},
+		{"/internal/prod", []raptor.TestRequestOption{raptor.WithClientCert(admin)}, http.StatusOK},
+		{"/internal/prod", []raptor.TestRequestOption{raptor.WithClientCert(staging)}, http.StatusForbidden},
+		{"/internal/prod", nil, http.StatusUnauthorized},
+	} {
+		if rec := app.TestGet(tc.path, tc.opts...); rec.Code != tc.want {
+			t.Errorf("GET %s with %d options: got %d, want %d", tc.path, len(tc.opts), rec.Code, tc.want)
+		}
+	}
+}
+
+func TestClientCertMiddlewareScoped(t *testing.T) {
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&PeersController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.UseOnly(&raptor.ClientCertMiddleware{Subjects: []string{"CN=*,O=Acme"}}, "Peers"),
+			},
+		},
+		router.CollectRoutes(router.Get("/whoami", "Peers.Whoami")),
+	)
+
+	if rec := app.TestGet("/whoami", raptor.WithClientCert(peerCert("billing", nil))); rec.Code != http.StatusOK {
+		t.Fatalf("matching subject: got %d", rec.Code)
+	}
+	other := peerCert("billing", nil)
+	other.Subject.Organization = []string{"Evil"}
+	if rec := app.TestGet("/whoami", raptor.WithClientCert(other)); rec.Code != http.StatusForbidden {
+		t.Fatalf("other organization: got %d, want 403", rec.Code)
+	}
+}
+
+func TestRequireClientCertFromYAML(t *testing.T) {
+	routes, err := router.ParseRoutesYAML([]byte(`
+routes:
+  /internal:
+    _client_cert:
+      sans: ["*.internal"]
+    /whoami: Peers.Whoami
+`))
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	app := newPeersApp(routes)
+
+	if rec := app.TestGet("/internal/whoami", raptor.WithClientCert(peerCert("x", []string{"billing.internal"}))); rec.Code != http.StatusOK {
+		t.Fatalf("matching SAN: got %d", rec.Code)
+	}
+	if rec := app.TestGet("/internal/whoami", raptor.WithClientCert(peerCert("x", []string{"billing.example.com"}))); rec.Code != http.StatusForbidden {
+		t.Fatalf("other SAN: got %d, want 403", rec.Code)
+	}
+}
+
+func TestClientCertMiddlewareRejectsInvalidPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("an invalid pattern should fail startup")
+		}
+	}()
+	newPeersApp(router.CollectRoutes(
+		router.Get("/whoami", "Peers.Whoami").With(router.RequireClientCert([]string{"[bad"}, nil)),
+	))
+}
@@ -25,20 +25,103 @@ type Config struct {
 
 type GeneralConfig struct {
 	LogLevel string `yaml:"log_level"`
+	// Debug exposes error causes and panic stack traces in responses.
+	// Never enable it in production.
+	Debug bool `yaml:"debug"`
 }
 
 type ServerConfig struct {
-	Address           string   `yaml:"address"`
-	Port              int      `yaml:"port"`
-	ShutdownTimeout   int      `yaml:"shutdown_timeout"`
-	ReadTimeout       int      `yaml:"read_timeout"`
-	ReadHeaderTimeout int      `yaml:"read_header_timeout"`
-	WriteTimeout      int      `yaml:"write_timeout"`
-	IdleTimeout       int      `yaml:"idle_timeout"`
-	MaxHeaderBytes    int      `yaml:"max_header_bytes"`
-	MaxBodyBytes      int64    `yaml:"max_body_bytes"`
-	IPExtractor       string   `yaml:"ip_extractor"`
-	TrustedProxies    []string `yaml:"trusted_proxies"`
+	Address           string           `yaml:"address"`
+	Port              int              `yaml:"port"`
+	ShutdownTimeout   int              `yaml:"shutdown_timeout"`
+	ReadTimeout       int              `yaml:"read_timeout"`
+	ReadHeaderTimeout int              `yaml:"read_header_timeout"`
+	WriteTimeout      int              `yaml:"write_timeout"`
+	IdleTimeout       int              `yaml:"idle_timeout"`
+	MaxHeaderBytes    int              `yaml:"max_header_bytes"`
+	MaxBodyBytes      int64            `yaml:"max_body_bytes"`
+	IPExtractor       string           `yaml:"ip_extractor"`
+	TrustedProxies    []string         `yaml:"trusted_proxies"`
+	OpenAPI           OpenAPIConfig    `yaml:"openapi"`
+	ErrorFormat       string           `yaml:"error_format"`
+	CORS              CORSConfig       `yaml:"cors"`
+	Versioning        VersioningConfig `yaml:"versioning"`
+	TLS               TLSConfig        `yaml:"tls"`
+	Listeners         []ListenerConfig `yaml:"listeners"`
+	HTTP2             HTTP2Config      `yaml:"http2"`
+}
+
+// OpenAPIConfig controls serving the generated OpenAPI document. It is served
+// only when Path is set; a .yaml or .yml suffix selects YAML over JSON.
+type OpenAPIConfig struct {
+	Path    string `yaml:"path"`
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// CORSConfig enables cross-origin resource sharing for the origins listed.
+// An origin may be "*" or contain one wildcard, as in "https://*.example.com".
+// Methods defaults to the methods a route serves, Headers to those the
+// preflight request asks for.
+type CORSConfig struct {
+	Origins       []string `yaml:"origins"`
+	Methods       []string `yaml:"methods"`
+	Headers       []string `yaml:"headers"`
+	ExposeHeaders []string `yaml:"expose_headers"`
+	Credentials   bool     `yaml:"credentials"`
+	MaxAge        int      `yaml:"max_age"`
+}
+
+// VersioningConfig controls how requests to the unprefixed path of a
+// versioned route pick the version: from Header, then from a vendor media
+// type in Accept (application/vnd.<Vendor>.<version>+json) when Vendor is
+// set, then Default.
+type VersioningConfig struct {
+	Default string `yaml:"default"`
+	Header  string `yaml:"header"`
+	Vendor  string `yaml:"vendor"`
+}
+
+// TLSConfig serves HTTPS when Cert and Key are set. Cert, Key, and ClientCA
+// take a file path or inline PEM; certificate files are reloaded when they
+// change. ClientAuth is one of none, request, require, verify_if_given, or
+// require_and_verify (the default when ClientCA is set). RedirectAddress,
+// e.g. ":80", adds a plain-HTTP listener redirecting to HTTPS.
+type TLSConfig struct {
+	Cert            string   `yaml:"cert"`
+	Key             string   `yaml:"key"`
+	MinVersion      string   `yaml:"min_version"`
+	CipherSuites    []string `yaml:"cipher_suites"`
+	ClientCA        string   `yaml:"client_ca"`
+	ClientAuth      string   `yaml:"client_auth"`
+	RedirectAddress string   `yaml:"redirect_address"`
+}
+
+// ListenerConfig is an address the server accepts connections on. Network
+// is tcp (the default), tcp4, tcp6, or unix; Address is host:port, or the
+// socket path for unix. Permissions sets a unix socket's file mode in octal,
+// e.g. "0660". When any listeners are configured they replace Address and
+// Port, and all of them serve the same routes.
+type ListenerConfig struct {
+	Network     string `yaml:"network"`
+	Address     string `yaml:"address"`
+	Permissions string `yaml:"permissions"`
+}
+
+// HTTP2Config tunes HTTP/2, which is served over TLS by default. H2C also
+// serves it unencrypted to clients that speak it with prior knowledge, such
+// as load balancers; HTTP/1.1 clients are unaffected. Zero values keep the
+// net/http defaults. MaxReadFrameSize must be between 16KiB and 16MiB, the
+// connection receive buffer at least 65535 bytes, and the receive buffers at
+// most 2^31-1 bytes. The timeouts are in seconds.
+type HTTP2Config struct {
+	H2C                           bool `yaml:"h2c"`
+	MaxConcurrentStreams          int  `yaml:"max_concurrent_streams"`
+	MaxReadFrameSize              int  `yaml:"max_read_frame_size"`
+	MaxReceiveBufferPerConnection int  `yaml:"max_receive_buffer_per_connection"`
+	MaxReceiveBufferPerStream     int  `yaml:"max_receive_buffer_per_stream"`
+	SendPingTimeout               int  `yaml:"send_ping_timeout"`
+	PingTimeout                   int  `yaml:"ping_timeout"`
 }
 
 type DatabaseConfig struct {
@@ -63,6 +146,14 @@ const (
 	DefaultServerConfigMaxHeaderBytes    = 1 << 20
 	DefaultServerConfigMaxBodyBytes      = int64(8 << 20) // explicit 0 disables the limit
 	DefaultServerConfigIPExtractor       = "direct"
+	DefaultServerConfigErrorFormat       = ErrorFormatLegacy
+	DefaultServerConfigVersioningHeader  = "Accept-Version"
+)
+
+// Error response formats for server.error_format.
+const (
+	ErrorFormatLegacy  = "legacy"  // {code, message, attrs}
+	ErrorFormatProblem = "problem" // RFC 9457 application/problem+json
 )
 
 var (
@@ -78,6 +169,11 @@ func (d DatabaseConfig) IsConfigured() bool {
 	return d.Name != ""
 }
 
+// Enabled reports whether HTTPS is configured.
+func (t TLSConfig) Enabled() bool {
+	return t.Cert != "" || t.Key != ""
+}
+
 func NewConfig(log *slog.Logger) (*Config, error) {
 	return loadConfig(log, slices.Concat(defaultConfigFiles, prodConfigFiles, devConfigFiles))
 }
@@ -102,6 +198,10 @@ func NewConfigDefaults() *Config {
 			MaxHeaderBytes:    DefaultServerConfigMaxHeaderBytes,
 			MaxBodyBytes:      DefaultServerConfigMaxBodyBytes,
 			IPExtractor:       DefaultServerConfigIPExtractor,
+			ErrorFormat:       DefaultServerConfigErrorFormat,
+			Versioning: VersioningConfig{
+				Header: DefaultServerConfigVersioningHeader,
+			},
 		},
 		DatabaseConfig: DatabaseConfig{},
 		AppConfig:      make(map[string]string),
@@ -234,6 +334,7 @@ func (c *Config) loadConfigFromFile(path string) error {
 
 func (c *Config) applyEnvironmentVariables() {
 	c.applyEnvironmentVariable("GENERAL_LOG_LEVEL", &c.GeneralConfig.LogLevel)
+	c.applyEnvironmentVariable("GENERAL_DEBUG", &c.GeneralConfig.Debug)
 
 	c.applyEnvironmentVariable("SERVER_ADDRESS", &c.ServerConfig.Address)
 	c.applyEnvironmentVariable("SERVER_PORT", &c.ServerConfig.Port)
@@ -246,6 +347,33 @@ func (c *Config) applyEnvironmentVariables() {
 	c.applyEnvironmentVariable("SERVER_MAX_BODY_BYTES", &c.ServerConfig.MaxBodyBytes)
 	c.applyEnvironmentVariable("SERVER_IP_EXTRACTOR", &c.ServerConfig.IPExtractor)
 	c.applyEnvironmentVariable("SERVER_TRUSTED_PROXIES", &c.ServerConfig.TrustedProxies)
+	c.applyEnvironmentVariable("SERVER_ERROR_FORMAT", &c.ServerConfig.ErrorFormat)
+	c.applyEnvironmentVariable("SERVER_OPENAPI_PATH", &c.ServerConfig.OpenAPI.Path)
+	c.applyEnvironmentVariable("SERVER_OPENAPI_TITLE", &c.ServerConfig.OpenAPI.Title)
+	c.applyEnvironmentVariable("SERVER_OPENAPI_VERSION", &c.ServerConfig.OpenAPI.Version)
+	c.applyEnvironmentVariable("SERVER_CORS_ORIGINS", &c.ServerConfig.CORS.Origins)
+	c.applyEnvironmentVariable("SERVER_CORS_METHODS", &c.ServerConfig.CORS.Methods)
+	c.applyEnvironmentVariable("SERVER_CORS_HEADERS", &c.ServerConfig.CORS.Headers)
+	c.applyEnvironmentVariable("SERVER_CORS_EXPOSE_HEADERS", &c.ServerConfig.CORS.ExposeHeaders)
+	c.applyEnvironmentVariable("SERVER_CORS_CREDENTIALS", &c.ServerConfig.CORS.Credentials)
+	c.applyEnvironmentVariable("SERVER_CORS_MAX_AGE", &c.ServerConfig.CORS.MaxAge)
+	c.applyEnvironmentVariable("SERVER_VERSIONING_DEFAULT", &c.ServerConfig.Versioning.Default)
+	c.applyEnvironmentVariable("SERVER_VERSIONING_HEADER", &c.ServerConfig.Versioning.Header)
+	c.applyEnvironmentVariable("SERVER_VERSIONING_VENDOR", &c.ServerConfig.Versioning.Vendor)
+	c.applyEnvironmentVariable("SERVER_TLS_CERT", &c.ServerConfig.TLS.Cert)
+	c.applyEnvironmentVariable("SERVER_TLS_KEY", &c.ServerConfig.TLS.Key)
+	c.applyEnvironmentVariable("SERVER_TLS_MIN_VERSION", &c.ServerConfig.TLS.MinVersion)
+	c.applyEnvironmentVariable("SERVER_TLS_CIPHER_SUITES", &c.ServerConfig.TLS.CipherSuites)
+	c.applyEnvironmentVariable("SERVER_TLS_CLIENT_CA", &c.ServerConfig.TLS.ClientCA)
+	c.applyEnvironmentVariable("SERVER_TLS_CLIENT_AUTH", &c.ServerConfig.TLS.ClientAuth)
+	c.applyEnvironmentVariable("SERVER_TLS_REDIRECT_ADDRESS", &c.ServerConfig.TLS.RedirectAddress)
+	c.applyEnvironmentVariable("SERVER_HTTP2_H2C", &c.ServerConfig.HTTP2.H2C)
+	c.applyEnvironmentVariable("SERVER_HTTP2_MAX_CONCURRENT_STREAMS", &c.ServerConfig.HTTP2.MaxConcurrentStreams)
+	c.applyEnvironmentVariable("SERVER_HTTP2_MAX_READ_FRAME_SIZE", &c.ServerConfig.HTTP2.MaxReadFrameSize)
+	c.applyEnvironmentVariable("SERVER_HTTP2_MAX_RECEIVE_BUFFER_PER_CONNECTION", &c.ServerConfig.HTTP2.MaxReceiveBufferPerConnection)
+	c.applyEnvironmentVariable("SERVER_HTTP2_MAX_RECEIVE_BUFFER_PER_STREAM", &c.ServerConfig.HTTP2.MaxReceiveBufferPerStream)
+	c.applyEnvironmentVariable("SERVER_HTTP2_SEND_PING_TIMEOUT", &c.ServerConfig.HTTP2.SendPingTimeout)
+	c.applyEnvironmentVariable("SERVER_HTTP2_PING_TIMEOUT", &c.ServerConfig.HTTP2.PingTimeout)
 
 	c.applyEnvironmentVariable("DATABASE_HOST", &c.DatabaseConfig.Host)
 	c.applyEnvironmentVariable("DATABASE_PORT", &c.DatabaseConfig.Port)
@@ -0,0 +1,22 @@
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+type apiVersionKey struct{}
+
+// WithAPIVersion returns a shallow copy of r that records version as the
+// API version it was routed to; see Context.APIVersion.
+func WithAPIVersion(r *http.Request, version string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), apiVersionKey{}, version))
+}
+
+// APIVersion returns the API version the request was routed to, from its
+// path prefix, version header, or media type, or the configured default.
+// It is empty for routes outside router.Version.
+func (c *Context) APIVersion() string {
+	version, _ := c.request.Context().Value(apiVersionKey{}).(string)
+	return version
+}
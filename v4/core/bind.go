@@ -0,0 +1,120 @@
+package core
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+// Decoder decodes the body of r into v. Decoders are selected by the
+// request's media type; see Core.RegisterDecoder.
+type Decoder func(r *http.Request, v any) error
+
+func defaultDecoders() map[string]Decoder {
+	return map[string]Decoder{
+		MIMEApplicationJSON: DecodeJSON,
+		MIMEApplicationXML:  DecodeXML,
+		MIMETextXML:         DecodeXML,
+		MIMEApplicationForm: DecodeForm,
+		MIMEMultipartForm:   DecodeForm,
+	}
+}
+
+// RegisterDecoder makes Bind use d for requests whose Content-Type has the
+// given media type, matched case-insensitively (parameters such as charset
+// are ignored), replacing any existing decoder for it. Register decoders
+// before the app starts serving.
+func (c *Core) RegisterDecoder(mediaType string, d Decoder) {
+	c.decoders[normalizeMediaType(mediaType)] = d
+}
+
+// Decoder returns the decoder registered for mediaType, if any.
+func (c *Core) Decoder(mediaType string) (Decoder, bool) {
+	d, ok := c.decoders[normalizeMediaType(mediaType)]
+	return d, ok
+}
+
+// normalizeMediaType lower-cases mediaType, which is case-insensitive, and
+// drops any parameters.
+func normalizeMediaType(mediaType string) string {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		return parsed
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// Bind decodes the request body into v using the decoder registered for the
+// request's Content-Type, defaulting to JSON when none is sent. Unknown media
+// types yield errs.ErrUnsupportedMediaType and malformed bodies a 400, while
+// oversized bodies keep their *http.MaxBytesError so they render as 413.
+func (c *Context) Bind(v any) error {
+	mediaType := MIMEApplicationJSON
+	if ct := c.request.Header.Get(HeaderContentType); ct != "" {
+		parsed, _, err := mime.ParseMediaType(ct)
+		if err != nil {
+			return errs.ErrUnsupportedMediaType
+		}
+		mediaType = parsed
+	}
+
+	decode, ok := c.core.Decoder(mediaType)
+	if !ok {
+		return errs.ErrUnsupportedMediaType
+	}
+	if err := decode(c.request, v); err != nil {
+		var e *errs.Error
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &e) || errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return errs.NewErrorBadRequest("Invalid request body").WithCause(err)
+	}
+	return nil
+}
+
+func DecodeJSON(r *http.Request, v any) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+func DecodeXML(r *http.Request, v any) error {
+	return xml.NewDecoder(r.Body).Decode(v)
+}
+
+// DecodeForm parses a URL-encoded or multipart form into v, which may be a
+// *url.Values, a *map[string]string, or a pointer to a struct whose fields
+// carry `form` tags (see BindParams for the supported field types).
+func DecodeForm(r *http.Request, v any) error {
+	if err := parseForm(r); err != nil {
+		return err
+	}
+
+	switch dst := v.(type) {
+	case *url.Values:
+		*dst = r.Form
+		return nil
+	case *map[string]string:
+		if *dst == nil {
+			*dst = make(map[string]string, len(r.Form))
+		}
+		for key := range r.Form {
+			(*dst)[key] = r.Form.Get(key)
+		}
+		return nil
+	}
+	val, binding, err := bindingOf(v)
+	if err != nil {
+		return err
+	}
+	return binding.bind(val, func(source bindSource, name string) []string {
+		if source != bindForm {
+			return nil
+		}
+		return r.Form[name]
+	})
+}
@@ -0,0 +1,267 @@
+package core
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+type bindSource uint8
+
+const (
+	bindPath bindSource = iota
+	bindQuery
+	bindHeader
+	bindCookie
+	bindForm
+)
+
+var bindTags = [...]string{
+	bindPath:   "path",
+	bindQuery:  "query",
+	bindHeader: "header",
+	bindCookie: "cookie",
+	bindForm:   "form",
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// bindings caches the field metadata of every struct type bound so far, so
+// reflection over struct tags happens once per type rather than per request.
+var bindings sync.Map // reflect.Type -> *structBinding
+
+type bindField struct {
+	index  []int
+	name   string
+	source bindSource
+	layout string
+}
+
+type structBinding struct {
+	fields  []bindField
+	sources [len(bindTags)]bool
+}
+
+// BindParams fills the fields of the struct pointed to by v from the request,
+// using the source named by each field's tag:
+//
+//	ID      int       `path:"id"`
+//	Page    int       `query:"page"`
+//	Tenant  string    `header:"X-Tenant"`
+//	Session string    `cookie:"session"`
+//	Name    string    `form:"name"`
+//	Since   time.Time `query:"since" layout:"2006-01-02"`
+//
+// Fields may be strings, bools, numbers, time.Time (RFC 3339 unless a layout
+// is given), time.Duration, encoding.TextUnmarshaler implementations, or
+// slices of and pointers to those. Parameters that are absent leave the field
+// untouched. Conversion failures return a 400 *errs.Error whose Attrs map
+// each offending parameter to a message.
+func (c *Context) BindParams(v any) error {
+	val, binding, err := bindingOf(v)
+	if err != nil {
+		return err
+	}
+	if binding.sources[bindForm] {
+		if err := parseForm(c.request); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return err
+			}
+			return errs.NewErrorBadRequest("Invalid form").WithCause(err)
+		}
+	}
+	return binding.bind(val, c.paramValues)
+}
+
+func (c *Context) paramValues(source bindSource, name string) []string {
+	switch source {
+	case bindPath:
+		if v := c.request.PathValue(name); v != "" {
+			return []string{v}
+		}
+	case bindQuery:
+		return c.Query()[name]
+	case bindHeader:
+		return c.request.Header.Values(name)
+	case bindCookie:
+		if cookie, err := c.request.Cookie(name); err == nil {
+			return []string{cookie.Value}
+		}
+	case bindForm:
+		return c.request.Form[name]
+	}
+	return nil
+}
+
+func bindingOf(v any) (reflect.Value, *structBinding, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("binding requires a non-nil pointer to a struct, got %T", v)
+	}
+	val = val.Elem()
+	return val, structBindingFor(val.Type()), nil
+}
+
+func structBindingFor(t reflect.Type) *structBinding {
+	if cached, ok := bindings.Load(t); ok {
+		return cached.(*structBinding)
+	}
+	b := &structBinding{}
+	b.collect(t, nil)
+	actual, _ := bindings.LoadOrStore(t, b)
+	return actual.(*structBinding)
+}
+
+func (b *structBinding) collect(t reflect.Type, parent []int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			b.collect(field.Type, index)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		for source, tag := range bindTags {
+			name := field.Tag.Get(tag)
+			if name == "" || name == "-" {
+				continue
+			}
+			b.fields = append(b.fields, bindField{
+				index:  index,
+				name:   name,
+				source: bindSource(source),
+				layout: field.Tag.Get("layout"),
+			})
+			b.sources[source] = true
+		}
+	}
+}
+
+func (b *structBinding) bind(val reflect.Value, lookup func(source bindSource, name string) []string) error {
+	var invalid map[string]any
+	for _, f := range b.fields {
+		values := lookup(f.source, f.name)
+		if len(values) == 0 {
+			continue
+		}
+		if err := setField(val.FieldByIndex(f.index), values, f.layout); err != nil {
+			if invalid == nil {
+				invalid = make(map[string]any)
+			}
+			invalid[f.name] = err.Error()
+		}
+	}
+	if invalid != nil {
+		e := errs.NewErrorBadRequest("Invalid request parameters")
+		e.Attrs = invalid
+		return e
+	}
+	return nil
+}
+
+func setField(field reflect.Value, values []string, layout string) error {
+	if field.Kind() == reflect.Slice && !isScalarType(field.Type()) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, raw := range values {
+			if err := setScalar(slice.Index(i), raw, layout); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setScalar(field, values[0], layout)
+}
+
+// isScalarType reports whether t binds from a single value despite being a
+// slice, e.g. a TextUnmarshaler such as net.IP.
+func isScalarType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func setScalar(field reflect.Value, raw, layout string) error {
+	typ := field.Type()
+	if raw == "" && typ.Kind() != reflect.String {
+		return nil
+	}
+
+	switch {
+	case typ.Kind() == reflect.Pointer:
+		elem := reflect.New(typ.Elem())
+		if err := setScalar(elem.Elem(), raw, layout); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	case typ == timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, raw)
+		if err != nil {
+			return fmt.Errorf("invalid time %q, expected layout %s", raw, layout)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case typ == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		field.SetInt(int64(d))
+		return nil
+	case isScalarType(typ):
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid %s %q", typ, raw)
+		}
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", raw)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, typ.Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", typ.Kind(), raw)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, typ.Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", typ.Kind(), raw)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, typ.Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", typ.Kind(), raw)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", typ)
+	}
+	return nil
+}
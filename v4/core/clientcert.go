@@ -0,0 +1,127 @@
+package core
+
+import (
+	"crypto/x509"
+	"fmt"
+	"path"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+// ClientCert describes the verified certificate a client presented over
+// mutual TLS.
+type ClientCert struct {
+	// Subject is the distinguished name, e.g. "CN=billing,O=Acme".
+	Subject        string
+	CommonName     string
+	DNSNames       []string
+	EmailAddresses []string
+	IPAddresses    []string
+	URIs           []string
+	// SPIFFEID is the first spiffe:// URI SAN, if any.
+	SPIFFEID string
+
+	Certificate *x509.Certificate
+}
+
+// SANs lists every subject alternative name: DNS names, email addresses,
+// IP addresses, and URIs.
+func (c *ClientCert) SANs() []string {
+	sans := make([]string, 0, len(c.DNSNames)+len(c.EmailAddresses)+len(c.IPAddresses)+len(c.URIs))
+	sans = append(sans, c.DNSNames...)
+	sans = append(sans, c.EmailAddresses...)
+	sans = append(sans, c.IPAddresses...)
+	return append(sans, c.URIs...)
+}
+
+func newClientCert(cert *x509.Certificate) *ClientCert {
+	c := &ClientCert{
+		Subject:        cert.Subject.String(),
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		Certificate:    cert,
+	}
+	for _, ip := range cert.IPAddresses {
+		c.IPAddresses = append(c.IPAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		c.URIs = append(c.URIs, uri.String())
+		if c.SPIFFEID == "" && uri.Scheme == "spiffe" {
+			c.SPIFFEID = uri.String()
+		}
+	}
+	return c
+}
+
+// ClientCert returns the client certificate verified during the TLS
+// handshake, or nil when the request is not over TLS or the client presented
+// no certificate that chains to server.tls.client_ca. Certificates accepted
+// without verification (client_auth request or require) are not reported.
+func (c *Context) ClientCert() *ClientCert {
+	state := c.request.TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return newClientCert(state.VerifiedChains[0][0])
+}
+
+// ClientCertMiddleware admits requests carrying a verified client
+// certificate, answering 401 otherwise. When Subjects or SANs are set the
+// certificate must also match one of their patterns, or the request gets 403.
+// Patterns use path.Match syntax, so "*" does not cross "/":
+//
+//	raptor.UseOnly(&raptor.ClientCertMiddleware{
+//		SANs: []string{"spiffe://example.org/ns/prod/sa/*"},
+//	}, "Payments")
+//
+// Subject patterns match the common name or the full distinguished name;
+// SAN patterns match any DNS, email, IP, or URI SAN. For per-route patterns
+// see router.RequireClientCert.
+type ClientCertMiddleware struct {
+	Middleware
+
+	Subjects []string
+	SANs     []string
+}
+
+func (m *ClientCertMiddleware) Setup() error {
+	for _, pattern := range append(append([]string(nil), m.Subjects...), m.SANs...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("client certificate pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (m *ClientCertMiddleware) Handle(ctx *Context, next func(*Context) error) error {
+	cert := ctx.ClientCert()
+	if cert == nil {
+		return errs.NewErrorUnauthorized("Client certificate required")
+	}
+	if !m.Allows(cert) {
+		ctx.Core().Resources.Log.Warn("Client certificate not authorized", "subject", cert.Subject, "sans", cert.SANs(), "controller", ctx.Controller(), "action", ctx.Action())
+		return errs.NewErrorForbidden("Client certificate not authorized")
+	}
+	return next(ctx)
+}
+
+// Allows reports whether cert matches the middleware's patterns. Without
+// patterns every verified certificate is allowed.
+func (m *ClientCertMiddleware) Allows(cert *ClientCert) bool {
+	if len(m.Subjects) == 0 && len(m.SANs) == 0 {
+		return true
+	}
+	return matchesAny(m.Subjects, cert.CommonName, cert.Subject) || matchesAny(m.SANs, cert.SANs()...)
+}
+
+func matchesAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -18,6 +18,8 @@ const (
 	MIMETextPlainCharsetUTF8             = MIMETextPlain + "; " + charsetUTF8
 	MIMEMultipartForm                    = "multipart/form-data"
 	MIMEOctetStream                      = "application/octet-stream"
+	// MIMEApplicationProblemJSON RFC 9457 problem details https://www.rfc-editor.org/rfc/rfc9457
+	MIMEApplicationProblemJSON = "application/problem+json"
 )
 
 const (
@@ -61,6 +63,9 @@ const (
 	HeaderOrigin              = "Origin"
 	HeaderCacheControl        = "Cache-Control"
 	HeaderConnection          = "Connection"
+	HeaderLink                = "Link"
+	HeaderDeprecation         = "Deprecation"
+	HeaderSunset              = "Sunset"
 
 	// Access control
 	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
@@ -2,7 +2,6 @@ package core
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -27,6 +26,11 @@ type Context struct {
 	controller string
 	action     string
 	handler    HandlerFunc
+
+	// errorHandled is set once the ErrorHandler has run, so an error
+	// passing up through middlewares is handled once even when the
+	// handler doesn't write a response.
+	errorHandled bool
 }
 
 const (
@@ -98,10 +102,6 @@ func (c *Context) Param(name string) string {
 	return c.request.PathValue(name)
 }
 
-func (c *Context) Bind(v any) error {
-	return json.NewDecoder(c.request.Body).Decode(v)
-}
-
 func (c *Context) Query() url.Values {
 	if c.query == nil {
 		c.query = c.request.URL.Query()
@@ -126,18 +126,22 @@ func (c *Context) FormValue(name string) string {
 }
 
 func (c *Context) FormParams() (url.Values, error) {
-	if strings.HasPrefix(c.request.Header.Get(HeaderContentType), MIMEMultipartForm) {
-		if err := c.request.ParseMultipartForm(defaultMemory); err != nil {
-			return nil, err
-		}
-	} else {
-		if err := c.request.ParseForm(); err != nil {
-			return nil, err
-		}
+	if err := parseForm(c.request); err != nil {
+		return nil, err
 	}
 	return c.request.Form, nil
 }
 
+// parseForm parses r's query and body into r.Form, reading the body as
+// multipart only for multipart/form-data. A body over the size limit fails
+// with its *http.MaxBytesError, which renders as 413.
+func parseForm(r *http.Request) error {
+	if strings.HasPrefix(r.Header.Get(HeaderContentType), MIMEMultipartForm) {
+		return r.ParseMultipartForm(defaultMemory)
+	}
+	return r.ParseForm()
+}
+
 func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
 	f, fh, err := c.request.FormFile(name)
 	if err != nil {
@@ -259,27 +263,15 @@ func (c *Context) Data(data any, status ...int) error {
 	return c.JSON(code, data)
 }
 
-// Error writes err as a JSON error response. Deliberate *errs.Error values
-// keep their message and status; anything else is logged server-side and
-// redacted to a generic 500 so internal details never reach the client.
+// Error hands err to the app's ErrorHandler unless a response has already
+// been committed or the handler has already run for this request. See
+// DefaultErrorHandler for the default policy.
 func (c *Context) Error(err error) {
-	if c.response.Committed {
+	if c.response.Committed || c.errorHandled {
 		return
 	}
-
-	var e *errs.Error
-	if !errors.As(err, &e) {
-		var maxBytesErr *http.MaxBytesError
-		if errors.As(err, &maxBytesErr) {
-			e = errs.NewErrorRequestEntityTooLarge("Request body too large")
-		} else {
-			c.core.Resources.Log.Error("Unhandled error in handler", "controller", c.controller, "action", c.action, "error", err)
-			e = errs.NewErrorInternal("Internal Server Error")
-		}
-	}
-	if writeErr := c.Data(e, e.Code); writeErr != nil {
-		c.core.Resources.Log.Error("Failed to write error response", "error", writeErr, "original", err)
-	}
+	c.errorHandled = true
+	c.core.ErrorHandler(c, err)
 }
 
 func (c *Context) Handler() HandlerFunc {
@@ -294,6 +286,7 @@ func (c *Context) ResetAndInit(r *http.Request, w http.ResponseWriter, controlle
 	c.response.init(w)
 	c.query = nil
 	c.handler = nil
+	c.errorHandled = false
 	c.routeStore = store
 	if len(c.store) > 0 {
 		clear(c.store)
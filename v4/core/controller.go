@@ -110,12 +110,20 @@ func (c *Core) validateController(controller any, controllerName string) error {
 	return nil
 }
 
+// registerControllerActions registers every exported method shaped like an
+// action: plain func(*Context) error, or typed func(*Context, Req) (Res, error)
+// which is adapted the same way Handle adapts functions.
 func (c *Core) registerControllerActions(val reflect.Value, controllerName string) {
 	for i := 0; i < val.NumMethod(); i++ {
 		method := val.Method(i)
-		if isActionMethod(method.Type()) {
-			action := val.Type().Method(i).Name
+		action := val.Type().Method(i).Name
+		switch t := method.Type(); {
+		case isActionMethod(t):
 			c.RegisterHandler(controllerName, action, method.Interface().(func(*Context) error))
+		case isTypedActionMethod(t):
+			c.RegisterHandler(controllerName, action, typedAction(method))
+			h := c.Handlers[controllerName][action]
+			h.Input, h.Output = t.In(1), t.Out(0)
 		}
 	}
 }
@@ -152,3 +160,9 @@ func (e *ErrorsController) NotFound(ctx *Context) error {
 func (e *ErrorsController) MethodNotAllowed(ctx *Context) error {
 	return errs.NewErrorMethodNotAllowed(fmt.Sprintf("Method %s not allowed for %s", ctx.Request().Method, ctx.Request().URL.Path))
 }
+
+// Options answers OPTIONS requests for paths no route serves with that
+// method; the router sets the Allow header.
+func (e *ErrorsController) Options(ctx *Context) error {
+	return ctx.NoContent()
+}
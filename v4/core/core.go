@@ -2,11 +2,13 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"runtime/debug"
 	"strings"
 	"sync"
 
+	"github.com/go-raptor/raptor/v4/config"
 	"github.com/go-raptor/raptor/v4/errs"
 )
 
@@ -16,16 +18,30 @@ type Core struct {
 	Services    map[string]ServiceInitializer
 	Middlewares []MiddlewareInitializer
 
-	serviceOrder []string
-	contextPool  *sync.Pool
-	IPExtractor  IPExtractor
+	serviceOrder    []string
+	middlewareNames []string
+	middlewareScope []ScopedMiddleware
+	contextPool     *sync.Pool
+	decoders        map[string]Decoder
+	renderers       *renderers
+	problemJSON     bool
+	cors            *corsPolicy
+	debug           bool
+	IPExtractor     IPExtractor
+	Validator       Validator
+	ErrorHandler    ErrorHandler
+	URLBuilder      URLBuilder
 }
 
 func NewCore(resources *Resources) *Core {
 	core := &Core{
-		Resources: resources,
-		Handlers:  make(map[string]map[string]*Handler),
-		Services:  make(map[string]ServiceInitializer),
+		Resources:    resources,
+		Handlers:     make(map[string]map[string]*Handler),
+		Services:     make(map[string]ServiceInitializer),
+		ErrorHandler: DefaultErrorHandler,
+		decoders:     defaultDecoders(),
+		renderers:    defaultRenderers(),
+		debug:        resources.Config.GeneralConfig.Debug,
 	}
 	core.contextPool = &sync.Pool{
 		New: func() any {
@@ -37,6 +53,19 @@ func NewCore(resources *Resources) *Core {
 		resources.Log.Error("Invalid trusted_proxies configuration", "error", err)
 		panic(err)
 	}
+	switch format := strings.ToLower(resources.Config.ServerConfig.ErrorFormat); format {
+	case config.ErrorFormatProblem:
+		core.problemJSON = true
+	case config.ErrorFormatLegacy, "":
+	default:
+		err := fmt.Errorf("invalid error_format %q (expected %q or %q)", format, config.ErrorFormatLegacy, config.ErrorFormatProblem)
+		resources.Log.Error("Invalid error_format configuration", "error", err)
+		panic(err)
+	}
+	if core.cors, err = newCORSPolicy(resources.Config.ServerConfig.CORS); err != nil {
+		resources.Log.Error("Invalid cors configuration", "error", err)
+		panic(err)
+	}
 	switch strings.ToLower(resources.Config.ServerConfig.IPExtractor) {
 	case "x-forwarded-for":
 		core.IPExtractor = ExtractIPFromXFFHeader(trusted)
@@ -59,7 +88,11 @@ func (c *Core) CompileHandlers() {
 }
 
 // Serve dispatches a request through h's precompiled middleware chain.
+// Requests for paths no route serves get no CORS headers.
 func (c *Core) Serve(w http.ResponseWriter, r *http.Request, h *Handler, controller, action, path string, store map[string]any) {
+	if c.cors != nil && !(controller == "ErrorsController" && action == "NotFound") && c.cors.handle(w, r) {
+		return
+	}
 	if max := c.Resources.Config.ServerConfig.MaxBodyBytes; max > 0 && r.Body != nil && r.Body != http.NoBody {
 		r.Body = http.MaxBytesReader(w, r.Body, max)
 	}
@@ -79,9 +112,10 @@ func (c *Core) finishRequest(ctx *Context) {
 			c.contextPool.Put(ctx)
 			panic(rec)
 		}
-		c.Resources.Log.Error("Panic recovered in handler", "controller", ctx.controller, "action", ctx.action, "panic", rec, "stack", string(debug.Stack()))
+		p := &panicError{value: rec, stack: debug.Stack()}
+		c.Resources.Log.Error("Panic recovered in handler", "controller", ctx.controller, "action", ctx.action, "panic", rec, "stack", string(p.stack))
 		if !ctx.response.Committed {
-			ctx.Error(errs.NewErrorInternal("Internal Server Error"))
+			ctx.Error(errs.NewErrorInternal("Internal Server Error").WithCause(p))
 		}
 	}
 	c.contextPool.Put(ctx)
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-raptor/raptor/v4/config"
+)
+
+// defaultCORSMethods are allowed in preflights when neither server.cors.methods
+// nor the route's Allow header says otherwise.
+const defaultCORSMethods = "GET, HEAD, POST, PUT, PATCH, DELETE"
+
+type corsPolicy struct {
+	origins     []string
+	anyOrigin   bool
+	methods     string
+	headers     string
+	expose      string
+	credentials bool
+	maxAge      string
+}
+
+// newCORSPolicy compiles cfg, returning nil when no origins are configured.
+func newCORSPolicy(cfg config.CORSConfig) (*corsPolicy, error) {
+	if len(cfg.Origins) == 0 {
+		return nil, nil
+	}
+	p := &corsPolicy{
+		methods:     joinHeaderValues(cfg.Methods, true),
+		headers:     joinHeaderValues(cfg.Headers, false),
+		expose:      joinHeaderValues(cfg.ExposeHeaders, false),
+		credentials: cfg.Credentials,
+	}
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+	for _, origin := range cfg.Origins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "":
+			continue
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Count(origin, "*") > 1:
+			return nil, fmt.Errorf("origin %q has more than one wildcard", origin)
+		default:
+			p.origins = append(p.origins, origin)
+		}
+	}
+	if p.anyOrigin && p.credentials {
+		return nil, fmt.Errorf("credentials cannot be allowed for every origin (\"*\")")
+	}
+	return p, nil
+}
+
+func joinHeaderValues(values []string, upper bool) string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			if upper {
+				v = strings.ToUpper(v)
+			}
+			out = append(out, v)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func (p *corsPolicy) allows(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range p.origins {
+		prefix, suffix, wildcard := strings.Cut(allowed, "*")
+		if !wildcard {
+			if origin == allowed {
+				return true
+			}
+			continue
+		}
+		if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// handle adds the CORS headers for r to w. It reports true when r was a
+// preflight request, which it answers with 204 No Content; a preflight for
+// a method that isn't allowed gets no CORS headers, so the browser fails it.
+func (p *corsPolicy) handle(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get(HeaderOrigin)
+	header := w.Header()
+	header.Add(HeaderVary, HeaderOrigin)
+	if origin == "" || !p.allows(origin) {
+		return false
+	}
+
+	requested := r.Header.Get(HeaderAccessControlRequestMethod)
+	if r.Method != http.MethodOptions || requested == "" {
+		p.allowOrigin(header, origin)
+		if p.expose != "" {
+			header.Set(HeaderAccessControlExposeHeaders, p.expose)
+		}
+		return false
+	}
+
+	header.Add(HeaderVary, HeaderAccessControlRequestMethod)
+	header.Add(HeaderVary, HeaderAccessControlRequestHeaders)
+	methods := p.methods
+	if methods == "" {
+		methods = header.Get(HeaderAllow)
+	}
+	if methods == "" {
+		methods = defaultCORSMethods
+	}
+	if !slices.Contains(strings.Split(methods, ", "), requested) {
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	p.allowOrigin(header, origin)
+	header.Set(HeaderAccessControlAllowMethods, methods)
+	if headers := p.headers; headers != "" {
+		header.Set(HeaderAccessControlAllowHeaders, headers)
+	} else if requested := r.Header.Get(HeaderAccessControlRequestHeaders); requested != "" {
+		header.Set(HeaderAccessControlAllowHeaders, requested)
+	}
+	if p.maxAge != "" {
+		header.Set(HeaderAccessControlMaxAge, p.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+func (p *corsPolicy) allowOrigin(header http.Header, origin string) {
+	if p.anyOrigin {
+		header.Set(HeaderAccessControlAllowOrigin, "*")
+	} else {
+		header.Set(HeaderAccessControlAllowOrigin, origin)
+	}
+	if p.credentials {
+		header.Set(HeaderAccessControlAllowCredentials, "true")
+	}
+}
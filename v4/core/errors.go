@@ -0,0 +1,120 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+// ErrorHandler turns an error returned (or panicked) by a handler into a
+// response. Replace Core.ErrorHandler to map domain errors, report to an
+// error tracker, or decorate bodies; delegate to DefaultErrorHandler for
+// anything left unhandled.
+type ErrorHandler func(ctx *Context, err error)
+
+// DefaultErrorHandler writes err with WriteError. Deliberate *errs.Error
+// values keep their message and status, an *http.MaxBytesError becomes 413,
+// errors mapped with errs.Register or errs.RegisterAs take their mapped
+// status, and anything else is logged server-side and redacted to a generic
+// 500 so internal details never reach the client.
+func DefaultErrorHandler(c *Context, err error) {
+	var e *errs.Error
+	if !errors.As(err, &e) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			e = errs.NewErrorRequestEntityTooLarge("Request body too large").WithCause(err)
+		} else if mapped, ok := errs.Map(err); ok {
+			e = mapped
+		} else {
+			c.core.Resources.Log.Error("Unhandled error in handler", "controller", c.controller, "action", c.action, "error", err)
+			e = errs.NewErrorInternal("Internal Server Error").WithCause(err)
+		}
+	}
+	if writeErr := c.WriteError(e); writeErr != nil {
+		c.core.Resources.Log.Error("Failed to write error response", "error", writeErr, "original", err)
+	}
+}
+
+// WriteError renders e in the configured error format: RFC 9457
+// application/problem+json, or the legacy body through content negotiation,
+// falling back to JSON when the client accepts none of the registered types.
+//
+// In debug mode (general.debug) the body also carries a "debug" attribute
+// holding e's cause chain and, for panics, the stack trace.
+func (c *Context) WriteError(e *errs.Error) error {
+	if c.core.debug {
+		e = withDebugInfo(e)
+	}
+	if c.core.problemJSON {
+		b, err := json.Marshal(e.Problem(c.request.URL.Path))
+		if err != nil {
+			return err
+		}
+		return c.Blob(e.Code, MIMEApplicationProblemJSON, b)
+	}
+	err := c.Negotiate(e.Code, e)
+	if errors.Is(err, errs.ErrNotAcceptable) {
+		return c.Data(e, e.Code)
+	}
+	return err
+}
+
+// panicError carries a recovered panic value and the stack it was raised on
+// as the cause of the 500 rendered for it.
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+func (p *panicError) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+func withDebugInfo(e *errs.Error) *errs.Error {
+	info := make(map[string]any)
+	if causes := causeChain(e.Unwrap()); len(causes) > 0 {
+		info["causes"] = causes
+	}
+	var p *panicError
+	if errors.As(e, &p) {
+		info["stack"] = string(p.stack)
+	}
+	if len(info) == 0 {
+		return e
+	}
+
+	debugged := *e
+	debugged.Attrs = make(map[string]any, len(e.Attrs)+1)
+	maps.Copy(debugged.Attrs, e.Attrs)
+	debugged.Attrs["debug"] = info
+	return &debugged
+}
+
+// causeChain lists the messages of err and everything it wraps, depth first.
+func causeChain(err error) []string {
+	var chain []string
+	for err != nil {
+		chain = append(chain, err.Error())
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				chain = append(chain, causeChain(inner)...)
+			}
+			return chain
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return chain
+		}
+	}
+	return chain
+}
@@ -2,11 +2,17 @@ package core
 
 import (
 	"net/http"
+	"reflect"
 	"slices"
 )
 
 type Handler struct {
-	Action      HandlerFunc
+	Action HandlerFunc
+	// Input and Output are the request and response types of typed
+	// actions; both are nil for plain func(*Context) error actions.
+	Input  reflect.Type
+	Output reflect.Type
+
 	middlewares []int
 	chain       HandlerFunc
 }
@@ -31,14 +37,28 @@ func (h *Handler) compile(middlewares []MiddlewareInitializer) {
 	h.chain = chain
 }
 
+// Guard returns a copy of h that runs guard before its middleware chain and
+// rejects the request with guard's error, if any. h must be compiled.
+func (h *Handler) Guard(guard HandlerFunc) *Handler {
+	guarded := *h
+	chain := h.chain
+	guarded.chain = func(ctx *Context) error {
+		if err := guard(ctx); err != nil {
+			return err
+		}
+		return chain(ctx)
+	}
+	return &guarded
+}
+
 // wrapErr commits the error response at the layer where the error occurred,
 // so outer middleware (loggers, metrics) observe the final status after
 // next() returns. The tradeoff: outer middleware cannot replace an error
-// response an inner layer has already written.
+// response an inner layer has already handled.
 func wrapErr(fn HandlerFunc) HandlerFunc {
 	return func(ctx *Context) error {
 		err := fn(ctx)
-		if err != nil && !ctx.Response().Committed {
+		if err != nil {
 			ctx.Error(err)
 		}
 		return err
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
+	"strings"
 )
 
+const middlewareSuffix = "Middleware"
+
 var middlewareType = reflect.TypeFor[Middleware]()
 
 type ScopedMiddleware struct {
@@ -13,6 +17,24 @@ type ScopedMiddleware struct {
 	Only       []string
 	Except     []string
 	Global     bool
+	// Routed registers the middleware without applying it to any action;
+	// routes and route groups opt in by referencing it.
+	Routed bool
+}
+
+// MiddlewareRef points a route at a middleware, either by the name of one
+// registered in Components.Middlewares ("Auth" or "AuthMiddleware") or by
+// instance, which is registered on first use if it isn't already.
+type MiddlewareRef struct {
+	Name       string
+	Middleware MiddlewareInitializer
+}
+
+func (r MiddlewareRef) String() string {
+	if r.Middleware != nil {
+		return reflect.TypeOf(r.Middleware).Elem().Name()
+	}
+	return NormalizeMiddleware(r.Name)
 }
 
 type Middlewares []ScopedMiddleware
@@ -50,6 +72,15 @@ func UseExcept(middleware MiddlewareInitializer, except ...string) ScopedMiddlew
 	}
 }
 
+// UseRouted registers middleware for routes and route groups to reference by
+// name, without applying it to any action on its own.
+func UseRouted(middleware MiddlewareInitializer) ScopedMiddleware {
+	return ScopedMiddleware{
+		Middleware: middleware,
+		Routed:     true,
+	}
+}
+
 func UseOnly(middleware MiddlewareInitializer, only ...string) ScopedMiddleware {
 	return ScopedMiddleware{
 		Middleware: middleware,
@@ -95,10 +126,144 @@ func (c *Core) registerMiddleware(scoped ScopedMiddleware, middlewareName string
 	}
 
 	c.Middlewares = append(c.Middlewares, scoped.Middleware)
+	c.middlewareNames = append(c.middlewareNames, middlewareName)
+	c.middlewareScope = append(c.middlewareScope, scoped)
 	c.applyMiddleware(len(c.Middlewares)-1, scoped)
 	return nil
 }
 
+// MiddlewareNames lists the middlewares wrapping h, outermost first.
+func (c *Core) MiddlewareNames(h *Handler) []string {
+	names := make([]string, 0, len(h.middlewares))
+	for _, index := range h.middlewares {
+		names = append(names, c.middlewareNames[index])
+	}
+	return names
+}
+
+func NormalizeMiddleware(name string) string {
+	if !strings.HasSuffix(name, middlewareSuffix) {
+		return name + middlewareSuffix
+	}
+	return name
+}
+
+// RouteHandler returns a handler for controller.action whose chain runs the
+// action's scoped middlewares followed by refs, compiled separately from the
+// shared action handler so other routes to the same action are unaffected.
+// Must be called after RegisterMiddlewares.
+func (c *Core) RouteHandler(controller, action string, refs []MiddlewareRef) (*Handler, error) {
+	base, ok := c.Handlers[controller][action]
+	if !ok {
+		return nil, fmt.Errorf("action %s not found", ActionDescriptor(controller, action))
+	}
+	if len(refs) == 0 {
+		return base, nil
+	}
+
+	h := &Handler{
+		Action:      base.Action,
+		Input:       base.Input,
+		Output:      base.Output,
+		middlewares: slices.Clone(base.middlewares),
+	}
+	if err := c.injectRefs(h, refs); err != nil {
+		return nil, err
+	}
+	h.compile(c.Middlewares)
+	return h, nil
+}
+
+// GuardAction returns a copy of h whose action runs guard first and fails
+// with guard's error, if any. Unlike Handler.Guard, the guard runs inside
+// the middleware chain, so middlewares observe its rejections.
+func (c *Core) GuardAction(h *Handler, guard HandlerFunc) *Handler {
+	action := h.Action
+	guarded := &Handler{
+		Action: func(ctx *Context) error {
+			if err := guard(ctx); err != nil {
+				return err
+			}
+			return action(ctx)
+		},
+		Input:       h.Input,
+		Output:      h.Output,
+		middlewares: slices.Clone(h.middlewares),
+	}
+	guarded.compile(c.Middlewares)
+	return guarded
+}
+
+// MountController is the controller name mounted http.Handlers run under;
+// the action is the mount prefix, see MountAction. Middlewares scoped with
+// UseOnly or UseExcept can target every mount as "Mount", or one as
+// "Mount./debug".
+const MountController = "MountController"
+
+// MountAction returns the action name of the mount at prefix: the prefix
+// with "." escaped as "%2E", so that "Mount./v1%2E0" names the mount at
+// "/v1.0" in an action descriptor.
+func MountAction(prefix string) string {
+	return strings.ReplaceAll(prefix, descriptorSeparator, "%2E")
+}
+
+// MountHandler returns a handler running fn as the pseudo-action
+// controller.action, which isn't registered on any controller: it is wrapped
+// in every registered middleware whose scope matches that descriptor,
+// followed by refs. Must be called after RegisterMiddlewares.
+func (c *Core) MountHandler(controller, action string, fn HandlerFunc, refs []MiddlewareRef) (*Handler, error) {
+	h := &Handler{Action: fn}
+	for i, scoped := range c.middlewareScope {
+		if matchesScope(scoped, controller, action) {
+			h.injectMiddleware(i)
+		}
+	}
+	if err := c.injectRefs(h, refs); err != nil {
+		return nil, err
+	}
+	h.compile(c.Middlewares)
+	return h, nil
+}
+
+func (c *Core) injectRefs(h *Handler, refs []MiddlewareRef) error {
+	for _, ref := range refs {
+		index, err := c.resolveMiddleware(ref)
+		if err != nil {
+			return err
+		}
+		h.injectMiddleware(index)
+	}
+	return nil
+}
+
+func (c *Core) resolveMiddleware(ref MiddlewareRef) (int, error) {
+	if ref.Middleware != nil {
+		if index := slices.Index(c.Middlewares, ref.Middleware); index >= 0 {
+			return index, nil
+		}
+		if err := c.registerMiddleware(UseRouted(ref.Middleware), ref.String()); err != nil {
+			return 0, err
+		}
+		return len(c.Middlewares) - 1, nil
+	}
+
+	name := NormalizeMiddleware(ref.Name)
+	index := -1
+	for i, registered := range c.middlewareNames {
+		if registered != name {
+			continue
+		}
+		if index >= 0 {
+			return 0, fmt.Errorf("middleware %s is ambiguous: several registered middlewares share that name", name)
+		}
+		index = i
+	}
+	if index < 0 {
+		return 0, fmt.Errorf("middleware %s is not registered (add it to Components.Middlewares, e.g. with UseRouted)", name)
+	}
+	return index, nil
+}
+
 func (c *Core) validateMiddleware(middleware any, middlewareName string) error {
 	val := reflect.ValueOf(middleware)
 	if val.Kind() != reflect.Pointer || val.IsNil() {
@@ -121,6 +286,9 @@ func (c *Core) validateScope(scoped ScopedMiddleware, middlewareName string) err
 	if scoped.Global {
 		set++
 	}
+	if scoped.Routed {
+		set++
+	}
 	if hasOnly {
 		set++
 	}
@@ -128,7 +296,7 @@ func (c *Core) validateScope(scoped ScopedMiddleware, middlewareName string) err
 		set++
 	}
 	if set != 1 {
-		return fmt.Errorf("%s: middleware must specify exactly one of Global, Only, or Except", middlewareName)
+		return fmt.Errorf("%s: middleware must specify exactly one of Global, Only, Except, or Routed", middlewareName)
 	}
 
 	descriptors := scoped.Only
@@ -140,6 +308,10 @@ func (c *Core) validateScope(scoped ScopedMiddleware, middlewareName string) err
 
 	for _, descriptor := range descriptors {
 		controller, action := ParseActionDescriptor(descriptor)
+		if controller == MountController {
+			// Mounts are registered with the routes, after middlewares.
+			continue
+		}
 
 		if _, ok := c.Handlers[controller]; !ok {
 			return fmt.Errorf("%s: controller '%s' in %s scope does not exist", middlewareName, controller, scopeType)
@@ -167,6 +339,9 @@ func matchesScope(scoped ScopedMiddleware, handlerController, handlerAction stri
 	if scoped.Global {
 		return true
 	}
+	if scoped.Routed {
+		return false
+	}
 
 	if len(scoped.Only) > 0 {
 		return matchesDescriptors(scoped.Only, handlerController, handlerAction)
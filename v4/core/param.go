@@ -0,0 +1,43 @@
+package core
+
+import (
+	"reflect"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+// ParamInt returns the path parameter name as an int, or a 400 *errs.Error
+// when it is missing or malformed.
+func (c *Context) ParamInt(name string) (int, error) {
+	return ParamAs[int](c, name)
+}
+
+// ParamUUID returns the path parameter name as a UUID, or a 400 *errs.Error
+// when it is missing or malformed.
+func (c *Context) ParamUUID(name string) (UUID, error) {
+	return ParamAs[UUID](c, name)
+}
+
+// ParamAs converts the path parameter name to T with the same rules as
+// BindParams: numbers, bools, time.Time (RFC 3339), durations, and
+// TextUnmarshalers. Missing or malformed values return a 400 *errs.Error
+// whose Attrs name the parameter.
+func ParamAs[T any](ctx *Context, name string) (T, error) {
+	var v T
+	raw := ctx.Param(name)
+	if raw == "" && reflect.TypeFor[T]().Kind() != reflect.String {
+		return v, InvalidParamError(name, "missing value")
+	}
+	if err := setScalar(reflect.ValueOf(&v).Elem(), raw, ""); err != nil {
+		return v, InvalidParamError(name, err.Error())
+	}
+	return v, nil
+}
+
+// InvalidParamError is the 400 error returned for a request parameter that
+// can't be converted, in the shape BindParams uses.
+func InvalidParamError(name, message string) *errs.Error {
+	e := errs.NewErrorBadRequest("Invalid request parameters")
+	e.Attrs = map[string]any{name: message}
+	return e
+}
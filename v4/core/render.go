@@ -0,0 +1,151 @@
+package core
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+// Renderer serializes v for a response. Renderers are selected from the
+// request's Accept header; see Core.RegisterRenderer.
+type Renderer func(v any) ([]byte, error)
+
+type renderers struct {
+	byType map[string]Renderer
+	order  []string
+}
+
+func defaultRenderers() *renderers {
+	r := &renderers{byType: make(map[string]Renderer)}
+	r.register(MIMEApplicationJSON, json.Marshal)
+	r.register(MIMEApplicationXML, xml.Marshal)
+	r.register(MIMETextXML, xml.Marshal)
+	return r
+}
+
+func (r *renderers) register(mediaType string, renderer Renderer) {
+	mediaType = normalizeMediaType(mediaType)
+	if _, exists := r.byType[mediaType]; !exists {
+		r.order = append(r.order, mediaType)
+	}
+	r.byType[mediaType] = renderer
+}
+
+// RegisterRenderer makes Negotiate offer mediaType (case-insensitive),
+// rendered by renderer, replacing any existing renderer for it. When the client accepts several
+// types equally, earlier registrations win; JSON is registered first.
+// Register renderers before the app starts serving.
+func (c *Core) RegisterRenderer(mediaType string, renderer Renderer) {
+	c.renderers.register(mediaType, renderer)
+}
+
+// Negotiate writes v with the status code, serialized by the registered
+// renderer that best matches the request's Accept header (honouring
+// q-values). Requests without an Accept header get JSON. When a renderer
+// can't serialize v, e.g. XML given a map, the next acceptable one is
+// tried. When none is acceptable, or none succeeds, it writes nothing and
+// returns errs.ErrNotAcceptable, carrying the last serialization error as
+// its cause.
+func (c *Context) Negotiate(code int, v any) error {
+	var err error
+	for _, mediaType := range c.core.renderers.negotiate(c.request.Header.Get(HeaderAccept)) {
+		var b []byte
+		if b, err = c.core.renderers.byType[mediaType](v); err != nil {
+			continue
+		}
+		c.response.Header().Add(HeaderVary, HeaderAccept)
+		return c.Blob(code, mediaType, b)
+	}
+	if err != nil {
+		return errs.ErrNotAcceptable.WithCause(err)
+	}
+	return errs.ErrNotAcceptable
+}
+
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+	index        int
+}
+
+func (a acceptRange) specificity() int {
+	switch {
+	case a.typ == "*":
+		return 0
+	case a.subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (a acceptRange) matches(mediaType string) bool {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	return (a.typ == "*" || a.typ == typ) && (a.subtype == "*" || a.subtype == subtype)
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for i, part := range strings.Split(header, ",") {
+		mediaRange, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mediaRange)), "/")
+		if !ok || typ == "" || subtype == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 && parsed <= 1 {
+					q = parsed
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{typ: typ, subtype: subtype, q: q, index: i})
+	}
+	return ranges
+}
+
+// negotiate ranks the registered media types the client accepts, most
+// preferred first. Each type takes the q-value of the most specific range
+// matching it, so "application/xml;q=0, */*" excludes XML; ties go to the
+// range listed first in the header, then to registration order.
+func (r *renderers) negotiate(header string) []string {
+	if strings.TrimSpace(header) == "" {
+		return r.order
+	}
+	ranges := parseAccept(header)
+
+	type candidate struct {
+		mediaType string
+		match     *acceptRange
+	}
+	var candidates []candidate
+	for _, mediaType := range r.order {
+		var match *acceptRange
+		for i := range ranges {
+			if ranges[i].matches(mediaType) && (match == nil || ranges[i].specificity() > match.specificity()) {
+				match = &ranges[i]
+			}
+		}
+		if match != nil && match.q > 0 {
+			candidates = append(candidates, candidate{mediaType, match})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.match.q != b.match.q {
+			return cmp.Compare(b.match.q, a.match.q)
+		}
+		return cmp.Compare(a.match.index, b.match.index)
+	})
+	mediaTypes := make([]string, len(candidates))
+	for i, c := range candidates {
+		mediaTypes[i] = c.mediaType
+	}
+	return mediaTypes
+}
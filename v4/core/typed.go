@@ -0,0 +1,112 @@
+package core
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// TypedHandlerFunc is an action that receives its input already bound and
+// returns its output for rendering.
+type TypedHandlerFunc[Req, Res any] func(ctx *Context, req Req) (Res, error)
+
+// Handle adapts a typed function into a HandlerFunc. The request is bound
+// into Req — body first, then path, query, header, cookie, and form tags, so
+// route parameters win over body fields — and validated when a validator is
+// registered. The result is rendered with Context.Data, or as 204 No Content
+// when it is a nil pointer, unless fn has already written the response
+// itself. Req may be a struct or a pointer to one.
+//
+// Controller methods with the signature func(*Context, Req) (Res, error) are
+// registered as typed actions automatically; Handle is for wrapping plain
+// functions.
+func Handle[Req, Res any](fn TypedHandlerFunc[Req, Res]) HandlerFunc {
+	reqType := reflect.TypeFor[Req]()
+	primeBinding(reqType)
+	return func(ctx *Context) error {
+		arg, target := newInput(reqType)
+		if err := ctx.bindInput(target); err != nil {
+			return err
+		}
+		res, err := fn(ctx, arg.Interface().(Req))
+		if err != nil {
+			return err
+		}
+		return ctx.renderResult(res)
+	}
+}
+
+func isTypedActionMethod(t reflect.Type) bool {
+	return t.NumIn() == 2 &&
+		t.In(0) == contextPtrType &&
+		t.NumOut() == 2 &&
+		t.Out(1) == errorType
+}
+
+func typedAction(method reflect.Value) HandlerFunc {
+	reqType := method.Type().In(1)
+	primeBinding(reqType)
+	return func(ctx *Context) error {
+		arg, target := newInput(reqType)
+		if err := ctx.bindInput(target); err != nil {
+			return err
+		}
+		out := method.Call([]reflect.Value{reflect.ValueOf(ctx), arg})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return err
+		}
+		return ctx.renderResult(out[0].Interface())
+	}
+}
+
+// newInput allocates a value of type t, returning it as the argument to
+// pass to the action and as the pointer to bind into.
+func newInput(t reflect.Type) (arg reflect.Value, target any) {
+	if t.Kind() == reflect.Pointer {
+		p := reflect.New(t.Elem())
+		return p, p.Interface()
+	}
+	p := reflect.New(t)
+	return p.Elem(), p.Interface()
+}
+
+// primeBinding resolves the struct binding for t at registration time so
+// the first request doesn't pay for the reflection.
+func primeBinding(t reflect.Type) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		structBindingFor(t)
+	}
+}
+
+func (c *Context) bindInput(v any) error {
+	if hasBody(c.request) {
+		if err := c.Bind(v); err != nil {
+			return err
+		}
+	}
+	if reflect.TypeOf(v).Elem().Kind() == reflect.Struct {
+		if err := c.BindParams(v); err != nil {
+			return err
+		}
+	}
+	if c.core.Validator != nil {
+		return c.Validate(v)
+	}
+	return nil
+}
+
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+func (c *Context) renderResult(res any) error {
+	if c.response.Committed {
+		return nil
+	}
+	if val := reflect.ValueOf(res); !val.IsValid() || (val.Kind() == reflect.Pointer && val.IsNil()) {
+		return c.NoContent()
+	}
+	return c.Data(res)
+}
@@ -0,0 +1,18 @@
+package core
+
+import "fmt"
+
+// URLBuilder builds the path of a named route from name/value pairs for its
+// wildcards. The router installs one on Core when routes are registered.
+type URLBuilder func(name string, params ...string) (string, error)
+
+// URLFor returns the path of the route called name, with its wildcards
+// filled from name/value pairs:
+//
+//	ctx.URLFor("user", "id", "42") // "/users/42"
+func (c *Context) URLFor(name string, params ...string) (string, error) {
+	if c.core.URLBuilder == nil {
+		return "", fmt.Errorf("route %q: no routes registered", name)
+	}
+	return c.core.URLBuilder(name, params...)
+}
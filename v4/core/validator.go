@@ -0,0 +1,82 @@
+package core
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+// Validator checks a bound request value. Implementations report per-field
+// problems by returning ValidationErrors; any other error is treated as a
+// single message about the whole value, and *errs.Error values pass through
+// unchanged.
+type Validator interface {
+	Validate(v any) error
+}
+
+// ValidatorFunc adapts a plain function to the Validator interface.
+type ValidatorFunc func(v any) error
+
+func (f ValidatorFunc) Validate(v any) error {
+	return f(v)
+}
+
+// ValidationErrors maps field names to the messages describing why they
+// failed validation.
+type ValidationErrors map[string][]string
+
+func (v ValidationErrors) Add(field, message string) {
+	v[field] = append(v[field], message)
+}
+
+func (v ValidationErrors) Error() string {
+	var b strings.Builder
+	for i, field := range slices.Sorted(maps.Keys(v)) {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(field + ": " + strings.Join(v[field], ", "))
+	}
+	return b.String()
+}
+
+// Validate runs the registered validator on v. Failures become a 422
+// *errs.Error whose Attrs map each field to its messages; other validator
+// errors are kept as the cause of a generic 422, so their text doesn't
+// reach the client. Without a validator it returns
+// errs.ErrValidatorNotRegistered.
+func (c *Context) Validate(v any) error {
+	if c.core.Validator == nil {
+		return errs.ErrValidatorNotRegistered
+	}
+	err := c.core.Validator.Validate(v)
+	if err == nil {
+		return nil
+	}
+
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return err
+	}
+	var fields ValidationErrors
+	if errors.As(err, &fields) {
+		out := errs.NewErrorUnprocessableEntity("Validation failed")
+		out.Attrs = make(map[string]any, len(fields))
+		for field, messages := range fields {
+			out.Attrs[field] = messages
+		}
+		return out
+	}
+	return errs.NewErrorUnprocessableEntity("Validation failed").WithCause(err)
+}
+
+// BindAndValidate binds the request body into v and validates the result.
+func (c *Context) BindAndValidate(v any) error {
+	if err := c.Bind(v); err != nil {
+		return err
+	}
+	return c.Validate(v)
+}
@@ -0,0 +1,122 @@
+package raptor_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+func newCORSApp(cors config.CORSConfig) *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&RoutesController{}}},
+		router.CollectRoutes(
+			router.Get("/hello", "Routes.Hello"),
+			router.Put("/hello", "Routes.Create"),
+		),
+		raptor.WithConfig(&config.Config{ServerConfig: config.ServerConfig{CORS: cors}}),
+	)
+}
+
+func TestCORSPreflight(t *testing.T) {
+	app := newCORSApp(config.CORSConfig{
+		Origins:     []string{"https://app.example.com", "https://*.example.org"},
+		Credentials: true,
+		MaxAge:      600,
+	})
+
+	rec := app.TestRequest(http.MethodOptions, "/hello", nil,
+		raptor.WithHeader("Origin", "https://app.example.com"),
+		raptor.WithHeader("Access-Control-Request-Method", "PUT"),
+		raptor.WithHeader("Access-Control-Request-Headers", "Content-Type, X-Token"),
+	)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("preflight: got %d, want 204", rec.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Methods":     "GET, HEAD, PUT, OPTIONS",
+		"Access-Control-Allow-Headers":     "Content-Type, X-Token",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	}
+	for key, value := range want {
+		if got := rec.Header().Get(key); got != value {
+			t.Fatalf("%s: got %q, want %q", key, got, value)
+		}
+	}
+
+	rec = app.TestRequest(http.MethodOptions, "/hello", nil,
+		raptor.WithHeader("Origin", "https://evil.example.net"),
+		raptor.WithHeader("Access-Control-Request-Method", "PUT"),
+	)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("disallowed origin got Access-Control-Allow-Origin %q", got)
+	}
+}
+
+func TestCORSDecoratesResponses(t *testing.T) {
+	app := newCORSApp(config.CORSConfig{
+		Origins:       []string{"https://*.example.org"},
+		ExposeHeaders: []string{"X-Request-Id"},
+	})
+
+	rec := app.TestGet("/hello", raptor.WithHeader("Origin", "https://shop.example.org"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /hello: got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example.org" {
+		t.Fatalf("Access-Control-Allow-Origin: got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-Id" {
+		t.Fatalf("Access-Control-Expose-Headers: got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Origin" {
+		t.Fatalf("Vary: got %q", got)
+	}
+
+	rec = app.TestGet("/hello", raptor.WithHeader("Origin", "https://example.org"))
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("bare domain should not match the wildcard, got %q", got)
+	}
+}
+
+func TestCORSPreflightForDisallowedMethod(t *testing.T) {
+	app := newCORSApp(config.CORSConfig{Origins: []string{"https://app.example.com"}})
+
+	rec := app.TestRequest(http.MethodOptions, "/hello", nil,
+		raptor.WithHeader("Origin", "https://app.example.com"),
+		raptor.WithHeader("Access-Control-Request-Method", "DELETE"),
+	)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("preflight: got %d, want 204", rec.Code)
+	}
+	for _, key := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods"} {
+		if got := rec.Header().Get(key); got != "" {
+			t.Fatalf("preflight for a method the route doesn't serve got %s %q", key, got)
+		}
+	}
+}
+
+func TestCORSSkipsUnknownPaths(t *testing.T) {
+	app := newCORSApp(config.CORSConfig{Origins: []string{"https://app.example.com"}})
+
+	rec := app.TestGet("/missing", raptor.WithHeader("Origin", "https://app.example.com"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /missing: got %d, want 404", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("404 got Access-Control-Allow-Origin %q", got)
+	}
+}
+
+func TestCORSRejectsWildcardWithCredentials(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected startup to fail")
+		}
+	}()
+	newCORSApp(config.CORSConfig{Origins: []string{"*"}, Credentials: true})
+}
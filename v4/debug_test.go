@@ -0,0 +1,29 @@
+package raptor
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWarnDebugAddresses(t *testing.T) {
+	for _, tc := range []struct {
+		addr string
+		warn bool
+	}{
+		{"127.0.0.1:3000", false},
+		{"[::1]:3000", false},
+		{"localhost:3000", false},
+		{"unix:/run/app.sock", false},
+		{"0.0.0.0:3000", true},
+		{"[::]:3000", true},
+		{"192.168.1.10:3000", true},
+	} {
+		var logs bytes.Buffer
+		warnDebugAddresses(slog.New(slog.NewTextHandler(&logs, nil)), []string{tc.addr})
+		if warned := strings.Contains(logs.String(), "level=WARN"); warned != tc.warn {
+			t.Errorf("%s: warned %v, want %v", tc.addr, warned, tc.warn)
+		}
+	}
+}
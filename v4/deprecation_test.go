@@ -0,0 +1,80 @@
+package raptor_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+func TestDeprecatedRouteHeaders(t *testing.T) {
+	app := newRoutesApp(router.CollectRoutes(
+		router.Get("/hello", "Routes.Hello"),
+		router.Get("/old/hello", "Routes.Hello").With(router.WithDeprecation(router.Deprecation{
+			Since:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Sunset: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			Link:   "https://example.com/migrate",
+		})),
+	))
+
+	rec := app.TestGet("/old/hello")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /old/hello: got %d", rec.Code)
+	}
+	want := map[string]string{
+		"Deprecation": "@1767225600",
+		"Sunset":      "Fri, 01 Jan 2027 00:00:00 GMT",
+		"Link":        `<https://example.com/migrate>; rel="deprecation"`,
+	}
+	for key, value := range want {
+		if got := rec.Header().Get(key); got != value {
+			t.Fatalf("%s: got %q, want %q", key, got, value)
+		}
+	}
+
+	if rec := app.TestGet("/hello"); rec.Header().Get("Deprecation") != "" {
+		t.Fatalf("non-deprecated route got Deprecation %q", rec.Header().Get("Deprecation"))
+	}
+}
+
+func TestDeprecatedRouteFromYAML(t *testing.T) {
+	routes, err := router.ParseRoutesYAML([]byte(`
+routes:
+  /old:
+    _deprecated:
+      since: 2026-01-01
+      sunset: 2027-01-01
+    /hello: Routes.Hello
+  /legacy:
+    _deprecated: 2026-01-01
+    /hello: Routes.Hello
+`))
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	app := newRoutesApp(routes)
+
+	rec := app.TestGet("/old/hello")
+	if rec.Header().Get("Deprecation") != "@1767225600" || rec.Header().Get("Sunset") != "Fri, 01 Jan 2027 00:00:00 GMT" {
+		t.Fatalf("headers: %v", rec.Header())
+	}
+	if rec := app.TestGet("/legacy/hello"); rec.Header().Get("Deprecation") != "@1767225600" {
+		t.Fatalf("date-only form: %v", rec.Header())
+	}
+}
+
+func TestDeprecationRequiresSince(t *testing.T) {
+	for _, doc := range []string{"_deprecated: true", "_deprecated: {sunset: 2027-01-01}"} {
+		if _, err := router.ParseRoutesYAML([]byte("routes:\n  /old:\n    " + doc + "\n    /hello: Routes.Hello\n")); err == nil {
+			t.Errorf("%s: expected an error", doc)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("a deprecation without Since should fail startup")
+		}
+	}()
+	newRoutesApp(router.Get("/old", "Routes.Hello").With(router.WithDeprecation(router.Deprecation{Link: "https://example.com"})))
+}
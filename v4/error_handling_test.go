@@ -2,7 +2,9 @@ package raptor_test
 
 import (
 	"bytes"
+	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -170,3 +172,111 @@ func TestDefaultMaxBodyBytes(t *testing.T) {
 		t.Fatalf("default MaxBodyBytes: got %d, want %d (8 MB)", got, int64(8<<20))
 	}
 }
+
+func (c *FaultController) NoRows(ctx *raptor.Context) error {
+	return fmt.Errorf("load user: %w", sql.ErrNoRows)
+}
+
+func TestCustomErrorHandler(t *testing.T) {
+	var reported []error
+	app := raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&FaultController{}}},
+		router.CollectRoutes(
+			router.Get("/norows", "Fault.NoRows"),
+			router.Get("/boom", "Fault.Boom"),
+		),
+		raptor.WithErrorHandler(func(ctx *raptor.Context, err error) {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.WriteError(errs.NewErrorNotFound("User not found"))
+				return
+			}
+			reported = append(reported, err)
+			raptor.DefaultErrorHandler(ctx, err)
+		}),
+	)
+
+	rec := app.TestGet("/norows")
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "User not found") {
+		t.Fatalf("domain error should map to 404: got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = app.TestGet("/boom")
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "secret-db-detail") {
+		t.Fatalf("delegating to the default handler should keep redaction: got %d %s", rec.Code, rec.Body.String())
+	}
+	if len(reported) != 1 {
+		t.Fatalf("custom handler should see unhandled errors, got %v", reported)
+	}
+}
+
+func TestErrorHandlerRunsOncePerRequest(t *testing.T) {
+	var calls, handled []string
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&FaultController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.Use(&TagMiddleware{tag: "outer", log: &calls}),
+				raptor.Use(&TagMiddleware{tag: "inner", log: &calls}),
+			},
+		},
+		router.CollectRoutes(router.Get("/boom", "Fault.Boom")),
+		raptor.WithErrorHandler(func(ctx *raptor.Context, err error) {
+			handled = append(handled, err.Error())
+		}),
+	)
+
+	app.TestGet("/boom")
+	if len(calls) != 2 || len(handled) != 1 {
+		t.Fatalf("a handler that writes nothing should still run once, got %d calls through %v", len(handled), calls)
+	}
+}
+
+func TestNilErrorHandlerKeepsDefault(t *testing.T) {
+	app := newFaultApp(nil, raptor.WithErrorHandler(nil))
+
+	if rec := app.TestGet("/teapot"); rec.Code != http.StatusTeapot {
+		t.Fatalf("got %d, want the default handler's 418", rec.Code)
+	}
+}
+
+var errShelfEmpty = errors.New("shelf empty")
+
+func (c *FaultController) Mapped(ctx *raptor.Context) error {
+	return fmt.Errorf("restock: %w", errShelfEmpty)
+}
+
+func TestRegisteredErrorMappingSkipsRedaction(t *testing.T) {
+	t.Cleanup(errs.Register(errShelfEmpty, func(error) *errs.Error {
+		return errs.NewErrorServiceUnavailable("Out of stock")
+	}))
+	app := raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&FaultController{}}},
+		router.CollectRoutes(router.Get("/mapped", "Fault.Mapped")),
+	)
+
+	rec := app.TestGet("/mapped")
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "Out of stock") {
+		t.Fatalf("registered mapping should apply: got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDebugModeExposesCausesAndStacks(t *testing.T) {
+	app := newFaultApp(nil, raptor.WithConfig(&config.Config{
+		GeneralConfig: config.GeneralConfig{Debug: true},
+	}))
+
+	rec := app.TestGet("/boom")
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "secret-db-detail") {
+		t.Fatalf("debug mode should expose the cause chain: got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = app.TestGet("/panic")
+	if body := rec.Body.String(); !strings.Contains(body, "secret-panic-detail") || !strings.Contains(body, "goroutine") {
+		t.Fatalf("debug mode should expose the panic value and stack: %s", body)
+	}
+
+	rec = app.TestGet("/teapot")
+	if body := rec.Body.String(); strings.Contains(body, "debug") {
+		t.Fatalf("errors without a cause need no debug info: %s", body)
+	}
+}
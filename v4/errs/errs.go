@@ -1,8 +1,13 @@
 package errs
 
 import (
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"maps"
 	"net/http"
+	"reflect"
+	"slices"
 )
 
 type Error struct {
@@ -10,6 +15,7 @@ type Error struct {
 	Message string         `json:"message,omitempty"`
 	Attrs   map[string]any `json:"attrs,omitempty"`
 	cause   error
+	typ     string
 }
 
 func (e *Error) Error() string {
@@ -34,6 +40,63 @@ func (e *Error) WithCause(cause error) *Error {
 	return &err
 }
 
+// WithType returns a copy of e carrying a problem type URI, reported as
+// "type" in RFC 9457 problem details.
+func (e *Error) WithType(uri string) *Error {
+	err := *e
+	err.typ = uri
+	return &err
+}
+
+// Problem is the RFC 9457 problem details representation of an Error.
+// Extensions are serialized as top-level members alongside the standard
+// ones, which they cannot override.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// Problem converts e to problem details for the request at instance. Type
+// defaults to "about:blank", Title is the status text, Detail the message,
+// and Attrs become extension members.
+func (e *Error) Problem(instance string) *Problem {
+	typ := e.typ
+	if typ == "" {
+		typ = "about:blank"
+	}
+	return &Problem{
+		Type:       typ,
+		Title:      http.StatusText(e.Code),
+		Status:     e.Code,
+		Detail:     e.Message,
+		Instance:   instance,
+		Extensions: e.Attrs,
+	}
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	members["type"] = p.Type
+	members["status"] = p.Status
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
 func NewError(code int, message string, attr ...any) *Error {
 	var attrs map[string]any
 	if len(attr) > 0 {
@@ -59,6 +122,57 @@ func NewError(code int, message string, attr ...any) *Error {
 	}
 }
 
+// MarshalXML encodes e as <error><code/><message/><attrs/></error>. Attrs
+// need custom handling since encoding/xml can't marshal maps: each becomes
+// an <attr name="..."> element, repeated per item for slice values.
+func (e *Error) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "error"}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := enc.EncodeElement(e.Code, xml.StartElement{Name: xml.Name{Local: "code"}}); err != nil {
+		return err
+	}
+	if e.Message != "" {
+		if err := enc.EncodeElement(e.Message, xml.StartElement{Name: xml.Name{Local: "message"}}); err != nil {
+			return err
+		}
+	}
+	if len(e.Attrs) > 0 {
+		attrs := xml.StartElement{Name: xml.Name{Local: "attrs"}}
+		if err := enc.EncodeToken(attrs); err != nil {
+			return err
+		}
+		for _, key := range slices.Sorted(maps.Keys(e.Attrs)) {
+			attr := xml.StartElement{
+				Name: xml.Name{Local: "attr"},
+				Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: key}},
+			}
+			for _, value := range attrValues(e.Attrs[key]) {
+				if err := enc.EncodeElement(fmt.Sprint(value), attr); err != nil {
+					return err
+				}
+			}
+		}
+		if err := enc.EncodeToken(attrs.End()); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func attrValues(value any) []any {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []any{value}
+	}
+	values := make([]any, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
+
 func (e *Error) AttrsToSlice() []any {
 	if e.Attrs == nil {
 		return nil
@@ -1,6 +1,7 @@
 package errs_test
 
 import (
+	"encoding/xml"
 	"errors"
 	"net/http"
 	"testing"
@@ -26,3 +27,16 @@ func TestWithCauseDoesNotMutateReceiver(t *testing.T) {
 		t.Fatalf("wrapped error lost fields: code=%d message=%q", wrapped.Code, wrapped.Message)
 	}
 }
+
+func TestErrorMarshalXML(t *testing.T) {
+	e := errs.NewErrorUnprocessableEntity("Validation failed", "name", []string{"is required", "too short"})
+
+	b, err := xml.Marshal(e)
+	if err != nil {
+		t.Fatalf("xml.Marshal: %v", err)
+	}
+	want := `<error><code>422</code><message>Validation failed</message><attrs><attr name="name">is required</attr><attr name="name">too short</attr></attrs></error>`
+	if string(b) != want {
+		t.Fatalf("got %s\nwant %s", b, want)
+	}
+}
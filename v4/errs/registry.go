@@ -0,0 +1,77 @@
+package errs
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// The registry maps library and domain errors to *Error values, so they
+// render with a meaningful status instead of being redacted to a 500.
+var (
+	registryMu sync.RWMutex
+	registry   []*mapping
+)
+
+type mapping struct {
+	match func(error) *Error
+}
+
+// Register maps every error matching target (per errors.Is) to the *Error
+// returned by fn; fn may return nil to leave an error unmapped. For example:
+//
+//	errs.Register(context.DeadlineExceeded, func(error) *errs.Error {
+//		return errs.NewErrorGatewayTimeout("Request timed out")
+//	})
+//
+// Mappings are consulted in registration order and the first match wins.
+// Register during startup; the registry is safe for concurrent use. The
+// returned func removes the mapping, e.g. in a test's t.Cleanup.
+func Register(target error, fn func(error) *Error) (unregister func()) {
+	return register(func(err error) *Error {
+		if errors.Is(err, target) {
+			return fn(err)
+		}
+		return nil
+	})
+}
+
+// RegisterAs maps every error whose chain contains a T (per errors.As) to
+// the *Error returned by fn, which receives the matched value. The returned
+// func removes the mapping.
+func RegisterAs[T error](fn func(T) *Error) (unregister func()) {
+	return register(func(err error) *Error {
+		var target T
+		if errors.As(err, &target) {
+			return fn(target)
+		}
+		return nil
+	})
+}
+
+func register(match func(error) *Error) func() {
+	m := &mapping{match: match}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, m)
+	return func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		registry = slices.DeleteFunc(registry, func(other *mapping) bool { return other == m })
+	}
+}
+
+// Map returns the *Error registered for err, carrying err as its cause.
+func Map(err error) (*Error, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, m := range registry {
+		if e := m.match(err); e != nil {
+			if e.cause == nil {
+				e = e.WithCause(err)
+			}
+			return e, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,62 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+var errQuotaExceeded = errors.New("quota exceeded")
+
+type rateLimitError struct {
+	retryAfter int
+}
+
+func (e *rateLimitError) Error() string { return "rate limited" }
+
+func TestRegisterMapsWrappedErrors(t *testing.T) {
+	t.Cleanup(errs.Register(errQuotaExceeded, func(error) *errs.Error {
+		return errs.NewErrorTooManyRequests("Quota exceeded")
+	}))
+
+	wrapped := fmt.Errorf("upload: %w", errQuotaExceeded)
+	e, ok := errs.Map(wrapped)
+	if !ok || e.Code != http.StatusTooManyRequests {
+		t.Fatalf("Map: got %v, %v", e, ok)
+	}
+	if !errors.Is(e, errQuotaExceeded) {
+		t.Fatal("mapped error should carry the original as its cause")
+	}
+
+	if _, ok := errs.Map(errors.New("unrelated")); ok {
+		t.Fatal("unregistered errors must not be mapped")
+	}
+}
+
+func TestRegisterAsReceivesMatchedValue(t *testing.T) {
+	t.Cleanup(errs.RegisterAs(func(e *rateLimitError) *errs.Error {
+		return errs.NewErrorTooManyRequests("Slow down", "retry_after", e.retryAfter)
+	}))
+
+	e, ok := errs.Map(fmt.Errorf("call: %w", &rateLimitError{retryAfter: 30}))
+	if !ok || e.Attrs["retry_after"] != 30 {
+		t.Fatalf("Map: got %v, %v", e, ok)
+	}
+}
+
+func TestUnregisterRemovesMapping(t *testing.T) {
+	errGone := errors.New("gone")
+	unregister := errs.Register(errGone, func(error) *errs.Error {
+		return errs.NewErrorNotFound("Gone")
+	})
+	if _, ok := errs.Map(errGone); !ok {
+		t.Fatal("registered error should be mapped")
+	}
+	unregister()
+	if _, ok := errs.Map(errGone); ok {
+		t.Fatal("unregistered mapping should no longer apply")
+	}
+}
@@ -0,0 +1,211 @@
+package raptor_test
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type GroupController struct {
+	raptor.Controller
+}
+
+func (c *GroupController) Tenant(ctx *raptor.Context) error {
+	tenant, _ := ctx.Get("tenant").(string)
+	return ctx.Data(map[string]string{"tenant": tenant})
+}
+
+func TestGroupMiddlewareAppliesOnlyInsideGroup(t *testing.T) {
+	var calls []string
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.Use(&TagMiddleware{tag: "global", log: &calls}),
+			},
+		},
+		router.CollectRoutes(
+			router.Get("/hello", "Routes.Hello"),
+			router.Group("/admin",
+				router.WithMiddleware(&TagMiddleware{tag: "admin", log: &calls}),
+				router.Get("/hello", "Routes.Hello"),
+				router.Group("/deep",
+					router.WithMiddleware(&TagMiddleware{tag: "deep", log: &calls}),
+					router.Get("/hello", "Routes.Hello"),
+				),
+			),
+		),
+	)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/hello", []string{"global"}},
+		{"/admin/hello", []string{"global", "admin"}},
+		{"/admin/deep/hello", []string{"global", "admin", "deep"}},
+	}
+	for _, tt := range tests {
+		calls = nil
+		if rec := app.TestGet(tt.path); rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: got %d, want 200", tt.path, rec.Code)
+		}
+		if !slices.Equal(calls, tt.want) {
+			t.Fatalf("GET %s: middleware calls %v, want %v", tt.path, calls, tt.want)
+		}
+	}
+}
+
+func TestGroupMiddlewareByName(t *testing.T) {
+	var calls []string
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.UseRouted(&TagMiddleware{tag: "tag", log: &calls}),
+			},
+		},
+		router.CollectRoutes(
+			router.Get("/hello", "Routes.Hello"),
+			router.Group("/tagged",
+				router.WithMiddlewareNamed("Tag"),
+				router.Get("/hello", "Routes.Hello"),
+			),
+		),
+	)
+
+	calls = nil
+	app.TestGet("/hello")
+	if len(calls) != 0 {
+		t.Fatalf("routed middleware ran outside its group: %v", calls)
+	}
+	app.TestGet("/tagged/hello")
+	if !slices.Equal(calls, []string{"tag"}) {
+		t.Fatalf("middleware calls %v, want [tag]", calls)
+	}
+}
+
+func TestGroupStore(t *testing.T) {
+	app := raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&GroupController{}}},
+		router.Group("/acme",
+			router.WithStore("tenant", "acme"),
+			router.Get("/tenant", "Group.Tenant"),
+		),
+	)
+
+	rec := app.TestGet("/acme/tenant")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"tenant":"acme"`) {
+		t.Fatalf("GET /acme/tenant: got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestYAMLRouteMiddleware(t *testing.T) {
+	var calls []string
+	routes, err := router.ParseRoutesYAML([]byte(`
+routes:
+  /hello: Routes.Hello
+  /admin:
+    _middleware: [Tag]
+    /hello:
+      GET: Routes.Hello
+`))
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.UseRouted(&TagMiddleware{tag: "tag", log: &calls}),
+			},
+		},
+		routes,
+	)
+
+	app.TestGet("/hello")
+	app.TestGet("/admin/hello")
+	if !slices.Equal(calls, []string{"tag"}) {
+		t.Fatalf("middleware calls %v, want [tag]", calls)
+	}
+}
+
+func TestGroupUnknownMiddlewareName(t *testing.T) {
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			t.Fatal("expected startup to fail for an unknown middleware name")
+		}
+		if err, _ := rec.(error); err == nil || !strings.Contains(err.Error(), "Missing") {
+			t.Fatalf("unexpected panic: %v", rec)
+		}
+	}()
+	raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&RoutesController{}}},
+		router.Group("/x",
+			router.WithMiddlewareNamed("Missing"),
+			router.Get("/hello", "Routes.Hello"),
+		),
+	)
+}
+
+type DenyMiddleware struct {
+	raptor.Middleware
+}
+
+func (m *DenyMiddleware) Handle(ctx *raptor.Context, next func(*raptor.Context) error) error {
+	if ctx.Request().Header.Get("Authorization") == "" {
+		return ctx.Status(http.StatusUnauthorized)
+	}
+	return next(ctx)
+}
+
+func TestPerRouteMiddlewareOnSharedAction(t *testing.T) {
+	app := raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&RoutesController{}}},
+		router.CollectRoutes(
+			router.Get("/public/hello", "Routes.Hello"),
+			router.Get("/private/hello", "Routes.Hello").With(router.WithMiddleware(&DenyMiddleware{})),
+		),
+	)
+
+	if rec := app.TestGet("/public/hello"); rec.Code != http.StatusOK {
+		t.Fatalf("GET /public/hello: got %d, want 200", rec.Code)
+	}
+	if rec := app.TestGet("/private/hello"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("GET /private/hello: got %d, want 401", rec.Code)
+	}
+	if rec := app.TestGet("/private/hello", raptor.WithHeader("Authorization", "token")); rec.Code != http.StatusOK {
+		t.Fatalf("GET /private/hello with auth: got %d, want 200", rec.Code)
+	}
+}
+
+func TestYAMLPerRouteMiddleware(t *testing.T) {
+	routes, err := router.ParseRoutesYAML([]byte(`
+routes:
+  /hello:
+    GET: Routes.Hello
+    POST: { action: Routes.Create, middleware: [Deny] }
+`))
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{raptor.UseRouted(&DenyMiddleware{})},
+		},
+		routes,
+	)
+
+	if rec := app.TestGet("/hello"); rec.Code != http.StatusOK {
+		t.Fatalf("GET /hello: got %d, want 200", rec.Code)
+	}
+	if rec := app.TestPost("/hello", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("POST /hello: got %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package raptor_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/core"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+func TestMountHandler(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vars", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("vars at " + r.URL.Path))
+	})
+
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.Use(&TagMiddleware{tag: "global", log: &calls}),
+				raptor.UseExcept(&TagMiddleware{tag: "not-mounts", log: &calls}, "Mount"),
+				raptor.UseOnly(&TagMiddleware{tag: "debug-only", log: &calls}, "Mount./debug"),
+			},
+		},
+		router.CollectRoutes(
+			router.Get("/hello", "Routes.Hello"),
+			router.Mount("/debug", mux),
+		),
+	)
+
+	rec := app.TestGet("/debug/vars")
+	if rec.Code != http.StatusOK || rec.Body.String() != "vars at /vars" {
+		t.Fatalf("GET /debug/vars: got %d %q", rec.Code, rec.Body.String())
+	}
+	if !slices.Equal(calls, []string{"global", "debug-only"}) {
+		t.Fatalf("middleware calls %v, want [global debug-only]", calls)
+	}
+
+	if rec := app.TestGet("/debug/missing"); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /debug/missing: got %d, want the mounted mux's 404", rec.Code)
+	}
+
+	var mounted *router.RouteInfo
+	for _, info := range app.RoutesTable() {
+		if info.Controller == core.MountController {
+			mounted = &info
+		}
+	}
+	if mounted == nil || mounted.Pattern != "/debug/{path...}" || !slices.Equal(mounted.Middlewares, []string{"TagMiddleware", "TagMiddleware"}) {
+		t.Fatalf("mount missing from routes table or wrong: %+v", mounted)
+	}
+}
+
+func TestMountKeepPrefixAndDottedPrefix(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1.0/api/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("items at " + r.URL.Path))
+	})
+
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.UseOnly(&TagMiddleware{tag: "v1.0-only", log: &calls}, "Mount./v1%2E0/api"),
+			},
+		},
+		router.CollectRoutes(
+			router.Get("/hello", "Routes.Hello"),
+			router.Mount("/v1.0/api", mux, router.KeepPrefix()),
+		),
+	)
+
+	rec := app.TestGet("/v1.0/api/items")
+	if rec.Code != http.StatusOK || rec.Body.String() != "items at /v1.0/api/items" {
+		t.Fatalf("GET /v1.0/api/items: got %d %q", rec.Code, rec.Body.String())
+	}
+	if !slices.Equal(calls, []string{"v1.0-only"}) {
+		t.Fatalf("middleware calls %v, want [v1.0-only]", calls)
+	}
+	if got := core.MountAction("/v1.0/api"); got != "/v1%2E0/api" {
+		t.Fatalf("MountAction: got %q", got)
+	}
+}
+
+func TestMountAtRoot(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /anything", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("root mux at " + r.URL.Path))
+	})
+	app := newRoutesApp(router.CollectRoutes(
+		router.Get("/hello", "Routes.Hello"),
+		router.Mount("/", mux),
+	))
+
+	if rec := app.TestGet("/anything"); rec.Code != http.StatusOK || rec.Body.String() != "root mux at /anything" {
+		t.Fatalf("GET /anything: got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := app.TestGet("/hello"); rec.Code != http.StatusOK {
+		t.Fatalf("routes should still take precedence over the root mount: got %d", rec.Code)
+	}
+}
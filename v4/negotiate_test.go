@@ -0,0 +1,115 @@
+package raptor_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/errs"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type negotiatedThing struct {
+	Name string `json:"name" xml:"name"`
+}
+
+type NegotiateController struct {
+	raptor.Controller
+}
+
+func (c *NegotiateController) Show(ctx *raptor.Context) error {
+	return ctx.Negotiate(http.StatusOK, negotiatedThing{Name: "rex"})
+}
+
+func (c *NegotiateController) Map(ctx *raptor.Context) error {
+	return ctx.Negotiate(http.StatusOK, map[string]string{"name": "rex"})
+}
+
+func (c *NegotiateController) Fail(ctx *raptor.Context) error {
+	return errs.NewErrorNotFound("no such thing")
+}
+
+func newNegotiateApp() *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&NegotiateController{}}},
+		router.CollectRoutes(
+			router.Get("/thing", "Negotiate.Show"),
+			router.Get("/map", "Negotiate.Map"),
+			router.Get("/fail", "Negotiate.Fail"),
+		),
+	)
+}
+
+func TestNegotiatePicksRendererFromAccept(t *testing.T) {
+	app := newNegotiateApp()
+
+	cases := []struct {
+		accept, contentType string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/xml", "application/xml"},
+		{"application/json;q=0.5, application/xml;q=0.9", "application/xml"},
+		{"text/html, text/*;q=0.8", "text/xml"},
+		{"application/json;q=0, */*;q=0.1", "application/xml"},
+	}
+	for _, tc := range cases {
+		rec := app.TestGet("/thing", raptor.WithHeader("Accept", tc.accept))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Accept %q: got %d", tc.accept, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tc.contentType {
+			t.Fatalf("Accept %q: Content-Type %q, want %q", tc.accept, got, tc.contentType)
+		}
+	}
+}
+
+func TestNegotiateNotAcceptable(t *testing.T) {
+	app := newNegotiateApp()
+
+	rec := app.TestGet("/thing", raptor.WithHeader("Accept", "image/png"))
+	if rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("got %d, want 406", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("the 406 body should fall back to JSON: %q", got)
+	}
+}
+
+func TestNegotiateFallsBackWhenRendererFails(t *testing.T) {
+	app := newNegotiateApp()
+
+	rec := app.TestGet("/map", raptor.WithHeader("Accept", "application/xml, application/json;q=0.9"))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" || rec.Body.String() != `{"name":"rex"}` {
+		t.Fatalf("XML can't render a map, so JSON should: got %d %q %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	if rec := app.TestGet("/map", raptor.WithHeader("Accept", "application/xml")); rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("no acceptable renderer succeeds: got %d, want 406", rec.Code)
+	}
+}
+
+func TestErrorResponseNegotiated(t *testing.T) {
+	app := newNegotiateApp()
+
+	rec := app.TestGet("/fail", raptor.WithHeader("Accept", "application/xml"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("got %d, want 404", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "<message>no such thing</message>") {
+		t.Fatalf("XML clients should get XML error bodies: %s", rec.Body.String())
+	}
+}
+
+func TestCustomRenderer(t *testing.T) {
+	app := newNegotiateApp()
+	app.Core.RegisterRenderer("Text/Plain", func(v any) ([]byte, error) {
+		return []byte(v.(negotiatedThing).Name), nil
+	})
+
+	rec := app.TestGet("/thing", raptor.WithHeader("Accept", "text/plain"))
+	if rec.Body.String() != "rex" || rec.Header().Get("Content-Type") != "text/plain" {
+		t.Fatalf("custom renderer not used: %q %q", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-raptor/raptor/v4/core"
+	"github.com/go-raptor/raptor/v4/errs"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+// OpenAPIController serves the generated document. The document is built on
+// the first request, once every route is registered, and cached.
+type OpenAPIController struct {
+	core.Controller
+
+	router *router.Router
+	info   Info
+	yaml   bool
+
+	once sync.Once
+	body []byte
+	err  error
+}
+
+// NewController returns a controller whose Document action serves the
+// document for r's routes, as YAML when path ends in .yaml or .yml and as
+// JSON otherwise.
+func NewController(r *router.Router, info Info, path string) *OpenAPIController {
+	return &OpenAPIController{
+		router: r,
+		info:   info,
+		yaml:   strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml"),
+	}
+}
+
+func (c *OpenAPIController) Document(ctx *core.Context) error {
+	c.once.Do(func() {
+		doc := Generate(c.router.Routes, ctx.Core(), c.info)
+		if c.yaml {
+			c.body, c.err = doc.YAML()
+		} else {
+			c.body, c.err = doc.JSON()
+		}
+	})
+	if c.err != nil {
+		return errs.NewErrorInternal("Failed to generate OpenAPI document").WithCause(c.err)
+	}
+	if c.yaml {
+		return ctx.Blob(http.StatusOK, "application/yaml", c.body)
+	}
+	return ctx.JSONBlob(http.StatusOK, c.body)
+}
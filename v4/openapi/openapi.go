@@ -0,0 +1,384 @@
+// Package openapi generates an OpenAPI 3.1 document from the routes an app
+// has registered, deriving schemas from typed actions and route metadata.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/core"
+	"github.com/go-raptor/raptor/v4/errs"
+	"github.com/go-raptor/raptor/v4/router"
+	"gopkg.in/yaml.v3"
+)
+
+const Version = "3.1.0"
+
+// Route.Store keys read by the generator. StoreRequest and StoreResponse
+// take a value (or reflect.Type) whose type describes the body, for actions
+// that aren't typed.
+const (
+	StoreSummary     = "openapi.summary"
+	StoreDescription = "openapi.description"
+	StoreRequest     = "openapi.request"
+	StoreResponse    = "openapi.response"
+)
+
+const (
+	DefaultTitle   = "API"
+	DefaultVersion = "1.0.0"
+)
+
+var anyMethods = []string{"get", "post", "put", "patch", "delete"}
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Servers     []Server             `json:"servers,omitempty"`
+}
+
+// Server is an alternative base URL for an operation; host-scoped routes
+// get one naming their host.
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Generate builds the document for routes, looking up each route's action
+// in c to find its typed input and output. Operations of host-scoped routes
+// list their host in servers. A document has one operation per path and
+// method, so when routes on different hosts share both, the first one is
+// described and the others are logged as a warning through c.
+func Generate(routes router.Routes, c *core.Core, info Info) *Document {
+	if info.Title == "" {
+		info.Title = DefaultTitle
+	}
+	if info.Version == "" {
+		info.Version = DefaultVersion
+	}
+
+	g := &generator{
+		schemas:      newSchemas(),
+		operationIDs: make(map[string]int),
+	}
+	errorType, errorRef := core.MIMEApplicationJSON, g.schemas.of(reflect.TypeFor[errs.Error]())
+	if c != nil && strings.EqualFold(c.Resources.Config.ServerConfig.ErrorFormat, config.ErrorFormatProblem) {
+		errorType, errorRef = core.MIMEApplicationProblemJSON, g.problemSchema()
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+	for _, route := range routes {
+		if route.Controller == "ErrorsController" || route.Mounted != nil {
+			continue
+		}
+		path, pathParams := convertPath(route.Path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+
+		methods := []string{strings.ToLower(route.Method)}
+		if route.Method == "ANY" || route.Method == "*" {
+			methods = anyMethods
+		}
+		var handler *core.Handler
+		if c != nil {
+			handler = c.Handlers[route.Controller][route.Action]
+		}
+		for _, method := range methods {
+			if existing, exists := item[method]; exists {
+				if c != nil && serverHost(existing) != route.Host {
+					c.Resources.Log.Warn("OpenAPI document omits a route on another host with the same path and method",
+						"method", strings.ToUpper(method), "path", route.Path, "host", route.Host, "described_host", serverHost(existing))
+				}
+				continue
+			}
+			item[method] = g.operation(route, method, pathParams, handler, errorType, errorRef)
+		}
+	}
+	doc.Components.Schemas = g.schemas.components
+	return doc
+}
+
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func (d *Document) YAML() ([]byte, error) {
+	// Round-trip through JSON so yaml.v3 sees plain maps and honours the
+	// json field names, including "$ref".
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(v)
+}
+
+type generator struct {
+	schemas      *schemas
+	operationIDs map[string]int
+}
+
+func (g *generator) operation(route router.Route, method string, pathParams []pathParam, handler *core.Handler, errorType string, errorRef *Schema) *Operation {
+	controller := strings.TrimSuffix(route.Controller, "Controller")
+	op := &Operation{
+		OperationID: g.operationID(controller + "." + route.Action),
+		Tags:        []string{controller},
+		Responses: map[string]*Response{
+			"default": {
+				Description: "Error",
+				Content:     map[string]MediaType{errorType: {Schema: errorRef}},
+			},
+		},
+	}
+	if s, ok := route.Store[StoreSummary].(string); ok {
+		op.Summary = s
+	}
+	if s, ok := route.Store[StoreDescription].(string); ok {
+		op.Description = s
+	}
+	_, op.Deprecated = route.Store[router.StoreDeprecation]
+	if route.Host != "" {
+		op.Servers = []Server{{URL: "//" + route.Host}}
+	}
+
+	input := storeType(route.Store[StoreRequest])
+	output := storeType(route.Store[StoreResponse])
+	if handler != nil {
+		if input == nil {
+			input = handler.Input
+		}
+		if output == nil {
+			output = handler.Output
+		}
+	}
+	inputStruct := derefStruct(input)
+
+	for _, p := range pathParams {
+		if p.constraint == "" {
+			p.constraint = route.Constraints[p.name]
+		}
+		param := Parameter{Name: p.name, In: "path", Required: true, Schema: constraintSchema(p.constraint)}
+		if f, ok := taggedField(inputStruct, "path", p.name); ok {
+			param.Schema = g.schemas.of(f.Type)
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+	if inputStruct != nil {
+		for _, in := range []string{"query", "header", "cookie"} {
+			for _, f := range taggedFields(inputStruct, in) {
+				op.Parameters = append(op.Parameters, Parameter{Name: f.Tag.Get(in), In: in, Schema: g.schemas.of(f.Type)})
+			}
+		}
+	}
+
+	if input != nil && method != "get" && method != "head" && method != "delete" {
+		var schema *Schema
+		if inputStruct != nil {
+			schema = g.schemas.object(inputStruct, isParamField)
+		} else {
+			schema = g.schemas.of(input)
+		}
+		if inputStruct == nil || len(schema.Properties) > 0 {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{core.MIMEApplicationJSON: {Schema: schema}},
+			}
+		}
+	}
+
+	if output != nil {
+		op.Responses[strconv.Itoa(http.StatusOK)] = &Response{
+			Description: http.StatusText(http.StatusOK),
+			Content:     map[string]MediaType{core.MIMEApplicationJSON: {Schema: g.schemas.of(output)}},
+		}
+	} else {
+		op.Responses[strconv.Itoa(http.StatusOK)] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+	return op
+}
+
+// problemSchema registers the RFC 9457 problem details schema, used for
+// errors when the app is configured with server.error_format: problem.
+func (g *generator) problemSchema() *Schema {
+	zero := 0
+	g.schemas.components["Problem"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"type":     {Type: "string", Format: "uri-reference"},
+			"title":    {Type: "string"},
+			"status":   {Type: "integer", Minimum: &zero},
+			"detail":   {Type: "string"},
+			"instance": {Type: "string", Format: "uri-reference"},
+		},
+		Required:             []string{"type", "status"},
+		AdditionalProperties: &Schema{},
+	}
+	return &Schema{Ref: componentSchemaRoot + "Problem"}
+}
+
+// serverHost returns the host an operation is scoped to, or "" for all.
+func serverHost(op *Operation) string {
+	if len(op.Servers) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(op.Servers[0].URL, "//")
+}
+
+// operationID keeps IDs unique when one action serves several routes.
+func (g *generator) operationID(id string) string {
+	g.operationIDs[id]++
+	if n := g.operationIDs[id]; n > 1 {
+		return fmt.Sprintf("%s%d", id, n)
+	}
+	return id
+}
+
+// convertPath turns a ServeMux pattern path into an OpenAPI path, returning
+// its wildcards: "/files/{path...}" becomes "/files/{path}", "/files/{$}"
+// becomes "/files/", and "/users/{id:int}" becomes "/users/{id}".
+func convertPath(path string) (string, []pathParam) {
+	var params []pathParam
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			continue
+		}
+		name, constraint, _ := strings.Cut(seg[1:len(seg)-1], ":")
+		name = strings.TrimSuffix(name, "...")
+		if name == "$" {
+			segments[i] = ""
+			continue
+		}
+		params = append(params, pathParam{name: name, constraint: constraint})
+		segments[i] = "{" + name + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+type pathParam struct {
+	name, constraint string
+}
+
+// constraintSchema describes the values a router constraint admits.
+func constraintSchema(constraint string) *Schema {
+	zero := 0
+	switch constraint {
+	case "int":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "uint":
+		return &Schema{Type: "integer", Minimum: &zero}
+	case "float":
+		return &Schema{Type: "number", Format: "double"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+	return &Schema{Type: "string"}
+}
+
+func storeType(v any) reflect.Type {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case reflect.Type:
+		return t
+	default:
+		return reflect.TypeOf(v)
+	}
+}
+
+func derefStruct(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+func taggedFields(t reflect.Type, tag string) []reflect.StructField {
+	var fields []reflect.StructField
+	for _, f := range reflect.VisibleFields(t) {
+		if name := f.Tag.Get(tag); f.IsExported() && name != "" && name != "-" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func taggedField(t reflect.Type, tag, name string) (reflect.StructField, bool) {
+	if t == nil {
+		return reflect.StructField{}, false
+	}
+	for _, f := range taggedFields(t, tag) {
+		if f.Tag.Get(tag) == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
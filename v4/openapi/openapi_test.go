@@ -0,0 +1,168 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/openapi"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type updateUserRequest struct {
+	ID      int    `path:"id"`
+	Verbose bool   `query:"verbose"`
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+}
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type UsersController struct {
+	raptor.Controller
+}
+
+func (c *UsersController) Update(ctx *raptor.Context, req updateUserRequest) (User, error) {
+	return User{ID: req.ID, Name: req.Name}, nil
+}
+
+func (c *UsersController) Files(ctx *raptor.Context) error {
+	return ctx.NoContent()
+}
+
+func newDocsApp(path string) *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&UsersController{}}},
+		router.CollectRoutes(
+			router.Put("/users/{id}", "Users.Update"),
+			router.Get("/files/{path...}", "Users.Files"),
+		),
+		raptor.WithConfig(&config.Config{
+			ServerConfig: config.ServerConfig{OpenAPI: config.OpenAPIConfig{Path: path, Title: "Users"}},
+		}),
+	)
+}
+
+func TestServedDocumentDescribesTypedRoutes(t *testing.T) {
+	app := newDocsApp("/openapi.json")
+
+	rec := app.TestGet("/openapi.json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", rec.Code)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" || doc.Info.Title != "Users" {
+		t.Fatalf("header: %+v %+v", doc.OpenAPI, doc.Info)
+	}
+
+	op := doc.Paths["/users/{id}"]["put"]
+	if op == nil {
+		t.Fatalf("PUT /users/{id} missing: %v", doc.Paths)
+	}
+	if op.OperationID != "Users.Update" {
+		t.Fatalf("operationId: %q", op.OperationID)
+	}
+	if len(op.Parameters) != 2 || op.Parameters[0].Name != "id" || op.Parameters[0].Schema.Type != "integer" || op.Parameters[1].In != "query" {
+		t.Fatalf("parameters: %+v", op.Parameters)
+	}
+	body := op.RequestBody.Content["application/json"].Schema
+	if _, ok := body.Properties["ID"]; ok || body.Properties["name"] == nil {
+		t.Fatalf("request body must hold only body fields: %+v", body.Properties)
+	}
+	if ref := op.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/User" {
+		t.Fatalf("response schema ref: %q", ref)
+	}
+	if ref := op.Responses["default"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Error" {
+		t.Fatalf("error schema ref: %q", ref)
+	}
+	if doc.Components.Schemas["Error"].Properties["code"] == nil {
+		t.Fatalf("errs.Error should be the shared error schema: %+v", doc.Components.Schemas["Error"])
+	}
+
+	files := doc.Paths["/files/{path}"]["get"]
+	if files == nil || len(files.Parameters) != 1 || files.Parameters[0].Name != "path" || !files.Parameters[0].Required {
+		t.Fatalf("{path...} wildcard should become a required path parameter: %+v", doc.Paths)
+	}
+}
+
+func TestServedDocumentAsYAML(t *testing.T) {
+	app := newDocsApp("/openapi.yaml")
+
+	rec := app.TestGet("/openapi.yaml")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "openapi: 3.1.0") || !strings.Contains(rec.Body.String(), "$ref: '#/components/schemas/User'") {
+		t.Fatalf("unexpected YAML document:\n%s", rec.Body.String())
+	}
+}
+
+func TestDocumentNotServedByDefault(t *testing.T) {
+	app := newDocsApp("")
+
+	if rec := app.TestGet("/openapi.json"); rec.Code != http.StatusNotFound {
+		t.Fatalf("got %d, want 404", rec.Code)
+	}
+}
+
+func TestGenerateUsesPathConstraints(t *testing.T) {
+	doc := openapi.Generate(router.CollectRoutes(
+		router.Get("/orders/{id:uuid}/lines/{n:int}", "Orders.Line"),
+	), nil, openapi.Info{})
+
+	op := doc.Paths["/orders/{id}/lines/{n}"]["get"]
+	if op == nil {
+		t.Fatalf("constrained path not converted: %v", doc.Paths)
+	}
+	if len(op.Parameters) != 2 || op.Parameters[0].Schema.Format != "uuid" || op.Parameters[1].Schema.Type != "integer" {
+		t.Fatalf("parameters: %+v %+v", op.Parameters[0].Schema, op.Parameters[1].Schema)
+	}
+}
+
+func TestGenerateHostScopedRoutes(t *testing.T) {
+	var logs bytes.Buffer
+	app := raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&UsersController{}}},
+		router.CollectRoutes(
+			router.Host("api.example.com",
+				router.Put("/users/{id}", "Users.Update"),
+				router.Get("/files/{path...}", "Users.Files"),
+			),
+			router.Host("admin.example.com", router.Put("/users/{id}", "Users.Update")),
+		),
+		raptor.WithConfig(&config.Config{
+			GeneralConfig: config.GeneralConfig{LogLevel: "warn"},
+			ServerConfig:  config.ServerConfig{OpenAPI: config.OpenAPIConfig{Path: "/openapi.json"}},
+		}),
+		raptor.WithLogHandler(func(level *slog.LevelVar) slog.Handler {
+			return slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: level})
+		}),
+	)
+
+	var doc openapi.Document
+	if err := json.Unmarshal(app.TestGet("/openapi.json").Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	files := doc.Paths["/files/{path}"]["get"]
+	if files == nil || len(files.Servers) != 1 || files.Servers[0].URL != "//api.example.com" {
+		t.Fatalf("host-scoped operation should name its host in servers: %+v", files)
+	}
+	users := doc.Paths["/users/{id}"]["put"]
+	if users == nil || len(users.Servers) != 1 || users.Servers[0].URL != "//api.example.com" {
+		t.Fatalf("the first route on a path and method should be described: %+v", users)
+	}
+	if !strings.Contains(logs.String(), "host=admin.example.com") {
+		t.Fatalf("the route on the other host should be logged as omitted:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,69 @@
+package raptor_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/errs"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type ProblemController struct {
+	raptor.Controller
+}
+
+func (c *ProblemController) Conflict(ctx *raptor.Context) error {
+	return errs.NewErrorConflict("email already taken", "field", "email").WithType("https://example.com/probs/taken")
+}
+
+func newProblemApp(format string) *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&ProblemController{}}},
+		router.CollectRoutes(router.Post("/users", "Problem.Conflict")),
+		raptor.WithConfig(&config.Config{ServerConfig: config.ServerConfig{ErrorFormat: format}}),
+	)
+}
+
+func TestProblemDetailsErrorFormat(t *testing.T) {
+	app := newProblemApp(config.ErrorFormatProblem)
+
+	rec := app.TestPost("/users", nil)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("got %d, want 409", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("Content-Type: %q", ct)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := map[string]any{
+		"type":     "https://example.com/probs/taken",
+		"title":    "Conflict",
+		"status":   float64(409),
+		"detail":   "email already taken",
+		"instance": "/users",
+		"field":    "email",
+	}
+	for k, v := range want {
+		if body[k] != v {
+			t.Fatalf("member %q: got %v, want %v (body %v)", k, body[k], v, body)
+		}
+	}
+}
+
+func TestLegacyErrorFormatIsDefault(t *testing.T) {
+	app := newProblemApp("")
+
+	rec := app.TestPost("/users", nil)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type: %q", ct)
+	}
+	if body := rec.Body.String(); body != `{"code":409,"message":"email already taken","attrs":{"field":"email"}}` {
+		t.Fatalf("legacy body changed: %s", body)
+	}
+}
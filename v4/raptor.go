@@ -5,16 +5,20 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"reflect"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/go-raptor/connectors"
 	"github.com/go-raptor/raptor/v4/config"
 	"github.com/go-raptor/raptor/v4/core"
+	"github.com/go-raptor/raptor/v4/openapi"
 	"github.com/go-raptor/raptor/v4/router"
 	"github.com/go-raptor/raptor/v4/server"
 )
@@ -27,10 +31,20 @@ type Raptor struct {
 	resources      *core.Resources
 	testMode       bool
 	configOverride *config.Config
+	coreOptions    []func(*core.Core)
 }
 
 type RaptorOption func(*Raptor)
 
+// EnvCmd names the environment variable that runs a one-off command instead
+// of serving, e.g. RAPTOR_CMD=routes to print the routes table and exit.
+// Commands don't open the database or run migrations, so service Setup hooks
+// must not rely on the database.
+const (
+	EnvCmd    = "RAPTOR_CMD"
+	CmdRoutes = "routes"
+)
+
 func New(components *core.Components, routes router.Routes, opts ...RaptorOption) *Raptor {
 	resources := core.NewResources()
 
@@ -60,9 +74,14 @@ func New(components *core.Components, routes router.Routes, opts ...RaptorOption
 	resources.SetConfig(cfg)
 
 	r.Core = core.NewCore(resources)
-	r.Server = server.NewServer(&r.Core.Resources.Config.ServerConfig, r.Router.Mux, resources.Log)
+	for _, opt := range r.coreOptions {
+		opt(r.Core)
+	}
+	r.Server = server.NewServer(&r.Core.Resources.Config.ServerConfig, r.Router, resources.Log)
+	components, routes = r.mountOpenAPI(components, routes)
 	r.configure(components)
 	r.registerRoutes(routes)
+	r.handleCommand()
 
 	return r
 }
@@ -83,6 +102,30 @@ func WithLogHandler(handler func(*slog.LevelVar) slog.Handler) RaptorOption {
 	}
 }
 
+// WithValidator registers the validator used by Context.Validate and
+// Context.BindAndValidate.
+func WithValidator(v core.Validator) RaptorOption {
+	return func(r *Raptor) {
+		r.coreOptions = append(r.coreOptions, func(c *core.Core) {
+			c.Validator = v
+		})
+	}
+}
+
+// WithErrorHandler replaces the handler that turns action errors into
+// responses; see core.DefaultErrorHandler for the default policy. A nil
+// handler keeps the default.
+func WithErrorHandler(h core.ErrorHandler) RaptorOption {
+	return func(r *Raptor) {
+		if h == nil {
+			return
+		}
+		r.coreOptions = append(r.coreOptions, func(c *core.Core) {
+			c.ErrorHandler = h
+		})
+	}
+}
+
 func (r *Raptor) Run() {
 	r.fatal(r.Server.Listen())
 	go func() {
@@ -92,15 +135,43 @@ func (r *Raptor) Run() {
 		}
 	}()
 	r.Core.Resources.Log.Info(fmt.Sprintf("🟢 Raptor %s is running on %s! 🦖💨", Version, r.Server.Address()))
+	if r.Core.Resources.Config.GeneralConfig.Debug {
+		warnDebugAddresses(r.Core.Resources.Log, r.Server.Addresses())
+	}
 	r.waitForShutdown()
 }
 
+// restartTimeout bounds how long a restart waits for the new process to
+// start listening before giving up and serving on.
+const restartTimeout = 30 * time.Second
+
+// waitForShutdown shuts down on SIGINT or SIGTERM. On SIGHUP or SIGUSR2 it
+// restarts without dropping connections: a new process inherits the
+// listeners, and once it is listening this one drains and exits.
 func (r *Raptor) waitForShutdown() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	restart := make(chan os.Signal, 1)
+	if len(restartSignals) > 0 {
+		signal.Notify(restart, restartSignals...)
+	}
 
-	<-quit
-	r.Core.Resources.Log.Warn("Shutting down Raptor...")
+	for waiting := true; waiting; {
+		select {
+		case <-quit:
+			r.Core.Resources.Log.Warn("Shutting down Raptor...")
+			waiting = false
+		case sig := <-restart:
+			r.Core.Resources.Log.Warn("Restarting Raptor...", "signal", sig.String())
+			pid, err := r.Server.Reexec(restartTimeout)
+			if err != nil {
+				r.Core.Resources.Log.Error("Restart failed, still serving", "error", err)
+				continue
+			}
+			r.Core.Resources.Log.Warn("New process is serving, draining this one", "pid", pid)
+			waiting = false
+		}
+	}
 	r.Shutdown()
 	r.Core.Resources.Log.Warn("Raptor exited, bye bye!")
 }
@@ -135,8 +206,12 @@ func (r *Raptor) Shutdown() {
 }
 
 func (r *Raptor) configure(components *core.Components) {
-	r.initDatabase(components)
-	r.handleMigrations(components)
+	// Commands only need the registered routes, so they leave the database
+	// closed and unmigrated.
+	if os.Getenv(EnvCmd) == "" {
+		r.initDatabase(components)
+		r.handleMigrations(components)
+	}
 	r.fatal(r.Core.RegisterServices(components))
 	r.fatal(r.Core.RegisterControllers(components))
 	r.fatal(r.Core.RegisterMiddlewares(components))
@@ -182,10 +257,77 @@ func (r *Raptor) initDatabase(components *core.Components) {
 	r.fatal(r.Core.Resources.Database.Init())
 }
 
+// mountOpenAPI adds the controller and route serving the generated OpenAPI
+// document when server.openapi.path is configured, leaving the caller's
+// components and routes untouched.
+func (r *Raptor) mountOpenAPI(components *core.Components, routes router.Routes) (*core.Components, router.Routes) {
+	cfg := r.Core.Resources.Config.ServerConfig.OpenAPI
+	if cfg.Path == "" {
+		return components, routes
+	}
+	withDocs := *components
+	withDocs.Controllers = append(slices.Clone(components.Controllers),
+		openapi.NewController(r.Router, openapi.Info{Title: cfg.Title, Version: cfg.Version}, cfg.Path))
+	return &withDocs, router.CollectRoutes(routes, router.Get(cfg.Path, "OpenAPI.Document"))
+}
+
 func (r *Raptor) registerRoutes(routes router.Routes) {
 	r.fatal(r.Router.RegisterRoutes(routes, r.Core))
 }
 
+// RoutesTable describes every registered route with the middlewares that
+// wrap it, outermost first.
+func (r *Raptor) RoutesTable() []router.RouteInfo {
+	return r.Router.Table()
+}
+
+func (r *Raptor) handleCommand() {
+	if r.testMode {
+		return
+	}
+	switch cmd := os.Getenv(EnvCmd); cmd {
+	case "":
+		return
+	case CmdRoutes:
+		if err := router.WriteTable(os.Stdout, r.RoutesTable()); err != nil {
+			r.Core.Resources.Log.Error("Failed to print routes", "error", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	default:
+		r.Core.Resources.Log.Error("Unknown command", "env", EnvCmd, "command", cmd)
+		os.Exit(1)
+	}
+}
+
+// warnDebugAddresses warns about each address other machines can reach,
+// since debug mode exposes error causes and stack traces.
+func warnDebugAddresses(log *slog.Logger, addresses []string) {
+	for _, addr := range addresses {
+		if !isLoopbackAddress(addr) {
+			log.Warn("Debug mode is enabled on a non-loopback address; error responses expose causes and stack traces", "address", addr)
+		}
+	}
+}
+
+// isLoopbackAddress reports whether addr, as listed by Server.Addresses,
+// is reachable only from this machine: a unix socket, localhost, or a
+// loopback IP.
+func isLoopbackAddress(addr string) bool {
+	if strings.HasPrefix(addr, "unix:") {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func Must[T any](v T, err error) T {
 	if err != nil {
 		panic(err)
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/go-raptor/raptor/v4/core"
+)
+
+// Constraint checks the raw value of a path wildcard, returning an error
+// describing why it doesn't fit.
+type Constraint func(value string) error
+
+var constraints = map[string]Constraint{
+	"int": func(v string) error {
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("invalid int %q", v)
+		}
+		return nil
+	},
+	"uint": func(v string) error {
+		if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+			return fmt.Errorf("invalid uint %q", v)
+		}
+		return nil
+	},
+	"float": func(v string) error {
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return fmt.Errorf("invalid float %q", v)
+		}
+		return nil
+	},
+	"bool": func(v string) error {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid bool %q", v)
+		}
+		return nil
+	},
+	"uuid": func(v string) error {
+		_, err := core.ParseUUID(v)
+		return err
+	},
+}
+
+// RegisterConstraint makes name usable in path wildcards as {param:name},
+// or {param...:name} to check the whole remaining path, replacing any
+// existing constraint of that name. The built-in constraints are int, uint,
+// float, bool, and uuid. Register constraints before routes are registered.
+// Constraints are checked after the route's middlewares, just before the
+// action, so loggers and metrics see the 400 responses.
+func RegisterConstraint(name string, c Constraint) {
+	constraints[name] = c
+}
+
+// splitConstraints strips constraints from the wildcards of path, returning
+// the ServeMux path and the constraint named for each wildcard.
+func splitConstraints(path string) (string, map[string]string, error) {
+	if !strings.Contains(path, ":") {
+		return path, nil, nil
+	}
+	var found map[string]string
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			continue
+		}
+		name, constraint, ok := strings.Cut(seg[1:len(seg)-1], ":")
+		if !ok {
+			continue
+		}
+		wildcard := name
+		name = strings.TrimSuffix(name, "...")
+		if _, exists := constraints[constraint]; !exists {
+			return "", nil, fmt.Errorf("unknown constraint %q on {%s}", constraint, name)
+		}
+		if found == nil {
+			found = make(map[string]string)
+		}
+		found[name] = constraint
+		segments[i] = "{" + wildcard + "}"
+	}
+	return strings.Join(segments, "/"), found, nil
+}
+
+// checkConstraints rejects requests whose path values break the route's
+// constraints.
+func checkConstraints(byParam map[string]string) core.HandlerFunc {
+	checks := maps.Clone(byParam)
+	return func(ctx *core.Context) error {
+		for param, name := range checks {
+			if err := constraints[name](ctx.Param(param)); err != nil {
+				return core.InvalidParamError(param, err.Error())
+			}
+		}
+		return nil
+	}
+}
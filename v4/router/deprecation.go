@@ -0,0 +1,77 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-raptor/raptor/v4/core"
+)
+
+// StoreDeprecation is the Route.Store key holding a route's Deprecation.
+const StoreDeprecation = "raptor.deprecation"
+
+// Deprecation marks a route as deprecated. Responses carry a Deprecation
+// header (RFC 9745), a Sunset header (RFC 8594) when Sunset is set, and a
+// Link to Link with rel="deprecation"; each hit is logged at warn level.
+// Since is required: RFC 9745 headers carry the deprecation date.
+type Deprecation struct {
+	Since  time.Time
+	Sunset time.Time
+	Link   string
+}
+
+// WithDeprecation marks the routes it applies to as deprecated:
+//
+//	router.Get("/old", "Users.Old").With(router.WithDeprecation(router.Deprecation{
+//		Since:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Sunset: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Link:   "https://example.com/docs/migrate",
+//	}))
+func WithDeprecation(d Deprecation) GroupOption {
+	return WithStore(StoreDeprecation, d)
+}
+
+func (d Deprecation) headers() http.Header {
+	h := make(http.Header)
+	h.Set(core.HeaderDeprecation, "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	if !d.Sunset.IsZero() {
+		h.Set(core.HeaderSunset, d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		h.Set(core.HeaderLink, fmt.Sprintf("<%s>; rel=\"deprecation\"", d.Link))
+	}
+	return h
+}
+
+// deprecationGuard decorates every response of a deprecated route and logs
+// the hit.
+func deprecationGuard(d Deprecation) core.HandlerFunc {
+	headers := d.headers()
+	return func(ctx *core.Context) error {
+		h := ctx.Response().Header()
+		for key, values := range headers {
+			h[key] = append(h[key], values...)
+		}
+		ctx.Core().Resources.Log.Warn("Deprecated route called", "controller", ctx.Controller(), "action", ctx.Action(), "path", ctx.Request().URL.Path)
+		return nil
+	}
+}
+
+// deprecationOf reads the Deprecation stored on a route.
+func deprecationOf(v any) (Deprecation, error) {
+	var d Deprecation
+	switch value := v.(type) {
+	case Deprecation:
+		d = value
+	case *Deprecation:
+		d = *value
+	default:
+		return Deprecation{}, fmt.Errorf("store key %s must hold a router.Deprecation, got %T", StoreDeprecation, v)
+	}
+	if d.Since.IsZero() {
+		return Deprecation{}, fmt.Errorf("deprecation needs a Since date")
+	}
+	return d, nil
+}
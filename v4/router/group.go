@@ -0,0 +1,123 @@
+package router
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/go-raptor/raptor/v4/core"
+)
+
+// GroupOption configures a Group. Routes are group options too, adding
+// themselves to the group.
+type GroupOption interface {
+	applyGroup(*group)
+}
+
+type group struct {
+	routes     Routes
+	middleware []core.MiddlewareRef
+	store      map[string]any
+}
+
+type groupOptionFunc func(*group)
+
+func (f groupOptionFunc) applyGroup(g *group) { f(g) }
+
+func (r Routes) applyGroup(g *group) {
+	g.routes = append(g.routes, r...)
+}
+
+// WithMiddleware runs the given middlewares on every route in the group.
+// They don't need to be listed in Components.Middlewares; unregistered ones
+// are initialized and injected when routes are registered.
+func WithMiddleware(middlewares ...core.MiddlewareInitializer) GroupOption {
+	return groupOptionFunc(func(g *group) {
+		for _, mw := range middlewares {
+			g.middleware = append(g.middleware, core.MiddlewareRef{Middleware: mw})
+		}
+	})
+}
+
+// WithMiddlewareNamed runs middlewares registered in Components.Middlewares
+// (typically with UseRouted) on every route in the group, by name.
+func WithMiddlewareNamed(names ...string) GroupOption {
+	return groupOptionFunc(func(g *group) {
+		for _, name := range names {
+			g.middleware = append(g.middleware, core.MiddlewareRef{Name: name})
+		}
+	})
+}
+
+// RequireClientCert admits only clients presenting a verified TLS
+// certificate whose subject matches one of subjects or whose SANs match one
+// of sans; with neither, any verified certificate is admitted. See
+// core.ClientCertMiddleware for the pattern syntax.
+//
+//	router.Post("/charges", "Payments.Charge").With(router.RequireClientCert(
+//		nil, []string{"spiffe://example.org/ns/prod/sa/checkout"},
+//	))
+func RequireClientCert(subjects, sans []string) GroupOption {
+	return WithMiddleware(&core.ClientCertMiddleware{Subjects: subjects, SANs: sans})
+}
+
+// WithStore sets key in the Store of every route in the group; values set
+// closer to a route take precedence.
+func WithStore(key string, value any) GroupOption {
+	return groupOptionFunc(func(g *group) {
+		if g.store == nil {
+			g.store = make(map[string]any)
+		}
+		g.store[key] = value
+	})
+}
+
+// Group prefixes every route in it with path, like Scope, and attaches the
+// group's middlewares and Store values to each of them. Group middlewares
+// run before those of nested groups and routes:
+//
+//	router.Group("/admin", router.WithMiddleware(auth),
+//		router.Get("/users", "Admin.Users"),
+//	)
+func Group(path string, opts ...GroupOption) Routes {
+	g := &group{}
+	for _, opt := range opts {
+		opt.applyGroup(g)
+	}
+
+	prefix := normalizePath(path)
+	routes := g.apply(g.routes)
+	for i := range routes {
+		routes[i].Path = normalizePath(prefix + "/" + routes[i].Path)
+	}
+	return routes
+}
+
+// apply returns copies of routes carrying the group's middlewares, ahead of
+// their own, and Store values, behind their own.
+func (g *group) apply(routes Routes) Routes {
+	result := make(Routes, 0, len(routes))
+	for _, r := range routes {
+		if len(g.middleware) > 0 {
+			r.Middleware = append(slices.Clone(g.middleware), r.Middleware...)
+		}
+		if len(g.store) > 0 {
+			store := maps.Clone(g.store)
+			maps.Copy(store, r.Store)
+			r.Store = store
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// With applies group options to these routes without changing their paths,
+// for middleware or Store values that belong to a single route:
+//
+//	router.Get("/me", "Users.Me").With(router.WithMiddleware(auth))
+func (r Routes) With(opts ...GroupOption) Routes {
+	g := &group{}
+	for _, opt := range opts {
+		opt.applyGroup(g)
+	}
+	return g.apply(append(slices.Clone(r), g.routes...))
+}
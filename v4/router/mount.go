@@ -0,0 +1,56 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/go-raptor/raptor/v4/core"
+)
+
+// MountOption configures a Mount.
+type MountOption func(*mountOptions)
+
+type mountOptions struct {
+	keepPrefix bool
+}
+
+// KeepPrefix passes requests to the mounted handler with the prefix still in
+// the path, for handlers that register full paths, like the pprof handlers
+// on http.DefaultServeMux.
+func KeepPrefix() MountOption {
+	return func(o *mountOptions) { o.keepPrefix = true }
+}
+
+// Mount serves handler for every method and every path under prefix, with
+// the prefix stripped from the request path unless KeepPrefix is given,
+// e.g. a file server, a gRPC gateway, or pprof:
+//
+//	router.Mount("/assets", http.FileServer(http.Dir("public")))
+//	router.Mount("/debug/pprof", http.DefaultServeMux, router.KeepPrefix()) // with net/http/pprof imported
+//
+// Requests still run through global and matching scoped middlewares (see
+// core.MountController), IP extraction, and error rendering for errors
+// middlewares return.
+func Mount(prefix string, handler http.Handler, opts ...MountOption) Routes {
+	var options mountOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	prefix = normalizePath(prefix)
+	path := prefix + "/{path...}"
+	if prefix == "/" {
+		path = "/{path...}"
+	} else if !options.keepPrefix {
+		handler = http.StripPrefix(prefix, handler)
+	}
+	return Routes{{
+		Method:     "ANY",
+		Path:       path,
+		Controller: core.MountController,
+		Action:     core.MountAction(prefix),
+		Mounted:    handler,
+	}}
+}
+
+func (r *Route) mountHandler(c *core.Core) (*core.Handler, error) {
+	return c.MountHandler(r.Controller, r.Action, core.WrapHandler(r.Mounted), r.Middleware)
+}
@@ -16,6 +16,23 @@ type Route struct {
 	Path       string
 	Controller string
 	Action     string
+
+	// Name identifies the route for URL generation; see Router.URL.
+	Name string
+	// Host restricts the route to requests for that host name, matched
+	// exactly and without the port. Empty matches every host.
+	Host string
+	// Version is the API version the route belongs to; see Version.
+	Version string
+	// Constraints maps wildcard names to the constraint their values must
+	// satisfy. Registration fills it from "{id:int}" wildcards in Path.
+	Constraints map[string]string
+	// Mounted, when set, serves the route instead of Controller.Action;
+	// see Mount.
+	Mounted http.Handler
+	// Middleware runs after the action's scoped middlewares, for this
+	// route only.
+	Middleware []core.MiddlewareRef
 }
 
 func NewRoute(method, path, controller, action string, store map[string]any) Route {
@@ -30,11 +47,14 @@ func NewRoute(method, path, controller, action string, store map[string]any) Rou
 
 func (r *Route) Pattern() string {
 	if r.Method == "ANY" || r.Method == "*" {
-		return r.Path
+		return r.Host + r.Path
 	}
-	return r.Method + " " + r.Path
+	return r.Method + " " + r.Host + r.Path
 }
 
 func (r *Route) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.Version != "" {
+		req = core.WithAPIVersion(req, r.Version)
+	}
 	r.core.Serve(w, req, r.handler, r.Controller, r.Action, r.Path, r.Store)
 }
@@ -2,6 +2,8 @@ package router
 
 import (
 	"fmt"
+	"maps"
+	"net"
 	"net/http"
 	"regexp"
 	"slices"
@@ -19,6 +21,7 @@ var pathRegex = regexp.MustCompile(`/+`)
 type Router struct {
 	Routes Routes
 	Mux    *http.ServeMux
+	names  map[string]*Route
 }
 
 func NewRouter() *Router {
@@ -27,30 +30,125 @@ func NewRouter() *Router {
 	}
 }
 
+// ServeHTTP dispatches req through Mux. Host names are case-insensitive but
+// ServeMux matches them exactly, so the request's host is lower-cased first,
+// like the hosts given to Host.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if host := strings.ToLower(req.Host); host != req.Host {
+		lowered := new(http.Request)
+		*lowered = *req
+		lowered.Host = host
+		req = lowered
+	}
+	r.Mux.ServeHTTP(w, req)
+}
+
 func (r *Router) RegisterRoutes(routes Routes, c *core.Core) error {
 	r.Routes = routes
+	r.names = make(map[string]*Route)
+	versioned := make(map[string]*versionDispatcher)
 	for i := range r.Routes {
 		route := &r.Routes[i]
 		if !isHTTPMethod(route.Method) {
 			return fmt.Errorf("invalid method %s on %s", route.Method, route.Path)
 		}
-		h, exists := c.Handlers[route.Controller][route.Action]
-		if !exists {
+		if strings.ContainsAny(route.Host, "/{} ") {
+			return fmt.Errorf("invalid host %q on %s %s", route.Host, route.Method, route.Path)
+		}
+		if _, _, err := net.SplitHostPort(route.Host); err == nil {
+			return fmt.Errorf("host %q on %s %s must not include a port: requests are matched by host name only", route.Host, route.Method, route.Path)
+		}
+		if route.Mounted == nil && !c.HasControllerAction(route.Controller, route.Action) {
 			return fmt.Errorf("action %s not found for %s %s", core.ActionDescriptor(route.Controller, route.Action), route.Method, route.Path)
 		}
+		path, found, err := splitConstraints(route.Path)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+		if found != nil {
+			route.Path = path
+			route.Constraints = maps.Clone(route.Constraints)
+			if route.Constraints == nil {
+				route.Constraints = make(map[string]string, len(found))
+			}
+			maps.Copy(route.Constraints, found)
+		}
+		if route.Version != "" {
+			plain := route.Pattern()
+			if versioned[plain] == nil {
+				versioned[plain] = &versionDispatcher{
+					core:     c,
+					config:   c.Resources.Config.ServerConfig.Versioning,
+					versions: make(map[string]*Route),
+					notFound: c.Handlers["ErrorsController"]["NotFound"],
+				}
+			}
+			versioned[plain].add(route)
+			route.Path = normalizePath("/" + route.Version + "/" + route.Path)
+		}
+		var h *core.Handler
+		if route.Mounted != nil {
+			h, err = route.mountHandler(c)
+		} else {
+			h, err = c.RouteHandler(route.Controller, route.Action, route.Middleware)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+		if len(route.Constraints) > 0 {
+			for param, name := range route.Constraints {
+				if _, exists := constraints[name]; !exists {
+					return fmt.Errorf("%s %s: unknown constraint %q on {%s}", route.Method, route.Path, name, param)
+				}
+			}
+			h = c.GuardAction(h, checkConstraints(route.Constraints))
+		}
+		if v, ok := route.Store[StoreDeprecation]; ok {
+			d, err := deprecationOf(v)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+			}
+			h = h.Guard(deprecationGuard(d))
+		}
+		if route.Name != "" {
+			if other, exists := r.names[route.Name]; exists {
+				return fmt.Errorf("route name %q used by both %s and %s", route.Name, other.Pattern(), route.Pattern())
+			}
+			r.names[route.Name] = route
+		}
 		route.core = c
 		route.handler = h
 		r.Mux.Handle(route.Pattern(), route)
 	}
+	if err := r.registerVersions(versioned); err != nil {
+		return err
+	}
+	c.URLBuilder = r.URL
 	return r.registerErrorHandlers(c)
 }
 
+// registerVersions serves the plain path of each versioned route through a
+// dispatcher picking the requested version.
+func (r *Router) registerVersions(versioned map[string]*versionDispatcher) error {
+	for _, route := range r.Routes {
+		if _, conflict := versioned[route.Pattern()]; conflict && route.Version == "" {
+			return fmt.Errorf("route %s conflicts with the unprefixed path of a versioned route", route.Pattern())
+		}
+	}
+	for pattern, dispatcher := range versioned {
+		r.Mux.Handle(pattern, dispatcher)
+	}
+	return nil
+}
+
 // registerErrorHandlers installs a catch-all fallback that renders 404s and,
-// when the path is served under other methods, 405s with an Allow header.
-// Skipped when the app registered its own catch-all route on "/".
+// when the path is served under other methods, 405s with an Allow header, or
+// 204s listing them for OPTIONS.
+// Skipped when the app registered its own catch-all route on "/", including
+// a Mount at "/".
 func (r *Router) registerErrorHandlers(c *core.Core) error {
 	for _, route := range r.Routes {
-		if route.Pattern() == "/" {
+		if pattern := route.Pattern(); pattern == "/" || pattern == "/{path...}" {
 			return nil
 		}
 	}
@@ -60,6 +158,7 @@ func (r *Router) registerErrorHandlers(c *core.Core) error {
 		core:       c,
 		notFound:   c.Handlers["ErrorsController"]["NotFound"],
 		notAllowed: c.Handlers["ErrorsController"]["MethodNotAllowed"],
+		options:    c.Handlers["ErrorsController"]["Options"],
 	})
 	return nil
 }
@@ -72,11 +171,17 @@ type fallbackHandler struct {
 	core       *core.Core
 	notFound   *core.Handler
 	notAllowed *core.Handler
+	options    *core.Handler
 }
 
 func (f *fallbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if allow := f.allowedMethods(req); allow != "" {
-		w.Header().Set("Allow", allow)
+		if req.Method == http.MethodOptions {
+			w.Header().Set(core.HeaderAllow, allow+", "+http.MethodOptions)
+			f.core.Serve(w, req, f.options, "ErrorsController", "Options", "/", nil)
+			return
+		}
+		w.Header().Set(core.HeaderAllow, allow)
 		f.core.Serve(w, req, f.notAllowed, "ErrorsController", "MethodNotAllowed", "/", nil)
 		return
 	}
@@ -116,6 +221,21 @@ func Scope(path string, routes ...Routes) Routes {
 	return result
 }
 
+// Host restricts routes to requests for host, e.g. "api.example.com", so
+// several sites can be served from one binary. Routes outside any Host
+// serve every host that has no more specific match.
+func Host(host string, routes ...Routes) Routes {
+	host = strings.ToLower(host)
+	var result Routes
+	for _, routeSet := range routes {
+		for _, r := range routeSet {
+			r.Host = host
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
 func MethodRoute(method, path, descriptor string) Routes {
 	controller, action := core.ParseActionDescriptor(descriptor)
 	return Routes{
@@ -123,6 +243,19 @@ func MethodRoute(method, path, descriptor string) Routes {
 	}
 }
 
+// Named sets the name used to build URLs for these routes with Router.URL
+// and Context.URLFor:
+//
+//	router.Get("/users/{id}", "Users.Show").Named("user")
+func (r Routes) Named(name string) Routes {
+	result := make(Routes, len(r))
+	for i, route := range r {
+		route.Name = name
+		result[i] = route
+	}
+	return result
+}
+
 func normalizePath(path string) string {
 	if path == "" || path == "/" {
 		return "/"
@@ -0,0 +1,60 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-raptor/raptor/v4/core"
+)
+
+// RouteInfo describes a registered route and the middlewares wrapping it.
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Pattern     string   `json:"pattern"`
+	Name        string   `json:"name,omitempty"`
+	Controller  string   `json:"controller"`
+	Action      string   `json:"action"`
+	Middlewares []string `json:"middlewares"`
+}
+
+// Table describes the registered routes in registration order, listing
+// each route's middlewares outermost first.
+func (r *Router) Table() []RouteInfo {
+	table := make([]RouteInfo, 0, len(r.Routes))
+	for i := range r.Routes {
+		route := &r.Routes[i]
+		info := RouteInfo{
+			Method:      route.Method,
+			Pattern:     route.Pattern(),
+			Name:        route.Name,
+			Controller:  route.Controller,
+			Action:      route.Action,
+			Middlewares: []string{},
+		}
+		if route.core != nil && route.handler != nil {
+			info.Middlewares = route.core.MiddlewareNames(route.handler)
+		}
+		table = append(table, info)
+	}
+	return table
+}
+
+// WriteTable prints table as aligned text columns.
+func WriteTable(w io.Writer, table []RouteInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tACTION\tNAME\tMIDDLEWARES")
+	for _, info := range table {
+		middlewares := strings.Join(info.Middlewares, " > ")
+		if middlewares == "" {
+			middlewares = "-"
+		}
+		name := info.Name
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", info.Method, strings.TrimPrefix(info.Pattern, info.Method+" "), core.ActionDescriptor(info.Controller, info.Action), name, middlewares)
+	}
+	return tw.Flush()
+}
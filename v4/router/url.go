@@ -0,0 +1,60 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URL returns the path of the route called name, filling its wildcards from
+// name/value pairs. Values are path-escaped; a {name...} wildcard keeps the
+// slashes in its value. Missing or unknown parameters are errors.
+//
+//	r.URL("file", "path", "docs/read me.txt") // "/files/docs/read%20me.txt"
+func (r *Router) URL(name string, params ...string) (string, error) {
+	route, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("route %q not found", name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("route %q: params must be name/value pairs", name)
+	}
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	segments := strings.Split(route.Path, "/")
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			continue
+		}
+		wildcard := seg[1 : len(seg)-1]
+		if wildcard == "$" {
+			segments[i] = ""
+			continue
+		}
+		param, rest := strings.CutSuffix(wildcard, "...")
+		value, ok := values[param]
+		if !ok || (value == "" && !rest) {
+			return "", fmt.Errorf("route %q: missing param %q", name, param)
+		}
+		delete(values, param)
+		segments[i] = escapePathValue(value, rest)
+	}
+	for param := range values {
+		return "", fmt.Errorf("route %q: unknown param %q", name, param)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func escapePathValue(value string, rest bool) string {
+	if !rest {
+		return url.PathEscape(value)
+	}
+	parts := strings.Split(value, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,91 @@
+package router
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/core"
+)
+
+// Version serves routes under the API version, e.g. "v2". Each route is
+// reachable at its path prefixed with the version ("/v2/users"), and at its
+// plain path ("/users"), where the version comes from the configured
+// version header (Accept-Version by default), a vendor media type in Accept
+// ("application/vnd.<vendor>.v2+json", when server.versioning.vendor is
+// set), or server.versioning.default. Several versions of a route may share
+// a plain path; a plain route may not.
+func Version(version string, routes ...Routes) Routes {
+	var result Routes
+	for _, routeSet := range routes {
+		for _, r := range routeSet {
+			r.Version = version
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// versionDispatcher serves a plain path shared by several versions of a
+// route, picking one per request.
+type versionDispatcher struct {
+	core     *core.Core
+	config   config.VersioningConfig
+	versions map[string]*Route
+	notFound *core.Handler
+}
+
+func (d *versionDispatcher) add(route *Route) {
+	d.versions[normalizeVersion(route.Version)] = route
+}
+
+func (d *versionDispatcher) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	header := d.config.Header
+	if header == "" {
+		header = config.DefaultServerConfigVersioningHeader
+	}
+	w.Header().Add(core.HeaderVary, header)
+	if d.config.Vendor != "" {
+		w.Header().Add(core.HeaderVary, core.HeaderAccept)
+	}
+
+	version := req.Header.Get(header)
+	if version == "" {
+		version = vendorVersion(req.Header.Get(core.HeaderAccept), d.config.Vendor)
+	}
+	if version == "" {
+		version = d.config.Default
+	}
+	if route, ok := d.versions[normalizeVersion(version)]; ok {
+		route.ServeHTTP(w, req)
+		return
+	}
+	d.core.Serve(w, req, d.notFound, "ErrorsController", "NotFound", "/", nil)
+}
+
+// vendorVersion extracts the version from the first vendor media type in
+// accept, e.g. "v2" from "application/vnd.acme.v2+json" for vendor "acme".
+func vendorVersion(accept, vendor string) string {
+	if vendor == "" || accept == "" {
+		return ""
+	}
+	prefix := "vnd." + strings.ToLower(vendor) + "."
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		_, subtype, _ := strings.Cut(mediaType, "/")
+		if rest, ok := strings.CutPrefix(subtype, prefix); ok {
+			version, _, _ := strings.Cut(rest, "+")
+			return version
+		}
+	}
+	return ""
+}
+
+// normalizeVersion lets "2", "v2", and "V2" name the same version.
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/go-raptor/raptor/v4/core"
 	"gopkg.in/yaml.v3"
 )
 
@@ -32,7 +34,7 @@ func ParseRoutesYAML(content []byte) (Routes, error) {
 	}
 
 	var routes Routes
-	if err := parseRoutes(config.Routes, "", &routes); err != nil {
+	if err := parseRoutes(config.Routes, "", yamlScope{}, &routes); err != nil {
 		return nil, err
 	}
 	return routes, nil
@@ -54,17 +56,204 @@ func ParseYAML(content []byte) Routes {
 	return routes
 }
 
-func parseRoutes(data map[string]any, parentPath string, routes *Routes) error {
+// Scope keys configure every route at a path and below it, like a Group.
+// In a path map they take yamlScopePrefix, so that bare words such as
+// "store" or "host" stay ordinary path segments; in the long form of a
+// method entry, which holds no paths, they are written bare.
+const (
+	yamlMiddlewareKey = "middleware"
+	yamlStoreKey      = "store"
+	yamlHostKey       = "host"
+	yamlDeprecatedKey = "deprecated"
+	yamlClientCertKey = "client_cert"
+
+	yamlScopePrefix = "_"
+)
+
+var yamlScopeKeys = []string{yamlMiddlewareKey, yamlStoreKey, yamlHostKey, yamlDeprecatedKey, yamlClientCertKey}
+
+// yamlScope carries the host, middleware, and Store values inherited from
+// enclosing path maps.
+type yamlScope struct {
+	host       string
+	middleware []core.MiddlewareRef
+	store      map[string]any
+}
+
+// extend applies the scope keys in data, each spelled prefix+name.
+func (s yamlScope) extend(data map[string]any, path, prefix string) (yamlScope, error) {
+	if value, ok := data[prefix+yamlHostKey]; ok {
+		host, ok := value.(string)
+		if !ok || host == "" {
+			return s, fmt.Errorf("routes YAML: %s under %q must be a host name", prefix+yamlHostKey, displayPath(path))
+		}
+		s.host = strings.ToLower(host)
+	}
+	if value, ok := data[prefix+yamlMiddlewareKey]; ok {
+		names, ok := value.([]any)
+		if !ok {
+			return s, fmt.Errorf("routes YAML: %s under %q must be a list of middleware names", prefix+yamlMiddlewareKey, displayPath(path))
+		}
+		s.middleware = slices.Clone(s.middleware)
+		for _, name := range names {
+			str, ok := name.(string)
+			if !ok || str == "" {
+				return s, fmt.Errorf("routes YAML: %s under %q must be a list of middleware names", prefix+yamlMiddlewareKey, displayPath(path))
+			}
+			s.middleware = append(s.middleware, core.MiddlewareRef{Name: str})
+		}
+	}
+	if value, ok := data[prefix+yamlStoreKey]; ok {
+		store, ok := value.(map[string]any)
+		if !ok {
+			return s, fmt.Errorf("routes YAML: %s under %q must be a map", prefix+yamlStoreKey, displayPath(path))
+		}
+		s.store = maps.Clone(s.store)
+		if s.store == nil {
+			s.store = make(map[string]any, len(store))
+		}
+		maps.Copy(s.store, store)
+	}
+	if value, ok := data[prefix+yamlDeprecatedKey]; ok {
+		d, err := parseYAMLDeprecation(value)
+		if err != nil {
+			return s, fmt.Errorf("routes YAML: %s under %q: %w", prefix+yamlDeprecatedKey, displayPath(path), err)
+		}
+		s.store = maps.Clone(s.store)
+		if s.store == nil {
+			s.store = make(map[string]any, 1)
+		}
+		s.store[StoreDeprecation] = d
+	}
+	if value, ok := data[prefix+yamlClientCertKey]; ok {
+		mw, err := parseYAMLClientCert(value)
+		if err != nil {
+			return s, fmt.Errorf("routes YAML: %s under %q: %w", prefix+yamlClientCertKey, displayPath(path), err)
+		}
+		s.middleware = append(slices.Clone(s.middleware), core.MiddlewareRef{Middleware: mw})
+	}
+	return s, nil
+}
+
+// parseYAMLClientCert accepts true or a map with subjects and sans pattern
+// lists.
+func parseYAMLClientCert(value any) (*core.ClientCertMiddleware, error) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return &core.ClientCertMiddleware{}, nil
+		}
+	case map[string]any:
+		mw := &core.ClientCertMiddleware{}
+		for key, field := range v {
+			var target *[]string
+			switch key {
+			case "subjects":
+				target = &mw.Subjects
+			case "sans":
+				target = &mw.SANs
+			default:
+				return nil, fmt.Errorf("unknown key %q", key)
+			}
+			patterns, ok := field.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of patterns", key)
+			}
+			for _, pattern := range patterns {
+				str, ok := pattern.(string)
+				if !ok || str == "" {
+					return nil, fmt.Errorf("%s must be a list of patterns", key)
+				}
+				*target = append(*target, str)
+			}
+		}
+		return mw, nil
+	}
+	return nil, fmt.Errorf("must be true or a map of subjects and sans")
+}
+
+// parseYAMLDeprecation accepts the since date alone, or a map with since,
+// sunset (dates or RFC 3339 timestamps), and link.
+func parseYAMLDeprecation(value any) (Deprecation, error) {
+	switch v := value.(type) {
+	case time.Time, string:
+		since, err := parseYAMLTime(v)
+		return Deprecation{Since: since}, err
+	case map[string]any:
+		var d Deprecation
+		for key, field := range v {
+			var err error
+			switch key {
+			case "since":
+				d.Since, err = parseYAMLTime(field)
+			case "sunset":
+				d.Sunset, err = parseYAMLTime(field)
+			case "link":
+				var ok bool
+				if d.Link, ok = field.(string); !ok {
+					err = fmt.Errorf("link must be a string")
+				}
+			default:
+				err = fmt.Errorf("unknown key %q", key)
+			}
+			if err != nil {
+				return Deprecation{}, err
+			}
+		}
+		if d.Since.IsZero() {
+			return Deprecation{}, fmt.Errorf("since is required")
+		}
+		return d, nil
+	}
+	return Deprecation{}, fmt.Errorf("must be a since date or a map of since, sunset, and link")
+}
+
+func parseYAMLTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %v", value)
+}
+
+func (s yamlScope) apply(routes Routes) Routes {
+	for i := range routes {
+		routes[i].Host = s.host
+		if len(s.middleware) > 0 {
+			routes[i].Middleware = slices.Clone(s.middleware)
+		}
+		if len(s.store) > 0 {
+			routes[i].Store = maps.Clone(s.store)
+		}
+	}
+	return routes
+}
+
+func parseRoutes(data map[string]any, parentPath string, scope yamlScope, routes *Routes) error {
+	scope, err := scope.extend(data, parentPath, yamlScopePrefix)
+	if err != nil {
+		return err
+	}
+
 	// Keys are visited in sorted order so route lists are deterministic.
 	for _, key := range slices.Sorted(maps.Keys(data)) {
 		value := data[key]
+		if name, ok := strings.CutPrefix(key, yamlScopePrefix); ok && slices.Contains(yamlScopeKeys, name) {
+			continue
+		}
 
 		if upper := strings.ToUpper(key); isHTTPMethod(upper) && !strings.HasPrefix(key, "/") {
-			descriptor, ok := value.(string)
-			if !ok {
-				return fmt.Errorf("routes YAML: %s under %q must map to a \"Controller.Action\" string", upper, displayPath(parentPath))
+			route, err := parseMethodRoute(upper, parentPath, value, scope)
+			if err != nil {
+				return err
 			}
-			*routes = append(*routes, MethodRoute(upper, parentPath, descriptor)...)
+			*routes = append(*routes, route...)
 			continue
 		}
 
@@ -72,11 +261,11 @@ func parseRoutes(data map[string]any, parentPath string, routes *Routes) error {
 
 		switch v := value.(type) {
 		case map[string]any:
-			if err := parseRoutes(v, path, routes); err != nil {
+			if err := parseRoutes(v, path, scope, routes); err != nil {
 				return err
 			}
 		case string:
-			*routes = append(*routes, MethodRoute("ANY", path, v)...)
+			*routes = append(*routes, scope.apply(MethodRoute("ANY", path, v))...)
 		default:
 			return fmt.Errorf("routes YAML: invalid value for path %q (expected a nested map or a \"Controller.Action\" string)", path)
 		}
@@ -84,6 +273,46 @@ func parseRoutes(data map[string]any, parentPath string, routes *Routes) error {
 	return nil
 }
 
+// Keys of the long form of a method entry:
+//
+//	GET: { action: Users.Show, name: user, middleware: [Auth] }
+const (
+	yamlActionKey = "action"
+	yamlNameKey   = "name"
+)
+
+func parseMethodRoute(method, path string, value any, scope yamlScope) (Routes, error) {
+	switch v := value.(type) {
+	case string:
+		return scope.apply(MethodRoute(method, path, v)), nil
+	case map[string]any:
+		descriptor, ok := v[yamlActionKey].(string)
+		if !ok || descriptor == "" {
+			return nil, fmt.Errorf("routes YAML: %s under %q must set %s to a \"Controller.Action\" string", method, displayPath(path), yamlActionKey)
+		}
+		for key := range v {
+			if key != yamlActionKey && key != yamlNameKey && !slices.Contains(yamlScopeKeys, key) {
+				return nil, fmt.Errorf("routes YAML: unknown key %q in %s under %q", key, method, displayPath(path))
+			}
+		}
+		scope, err := scope.extend(v, path, "")
+		if err != nil {
+			return nil, err
+		}
+		routes := scope.apply(MethodRoute(method, path, descriptor))
+		if value, ok := v[yamlNameKey]; ok {
+			name, ok := value.(string)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("routes YAML: %s of %s under %q must be a string", yamlNameKey, method, displayPath(path))
+			}
+			routes = routes.Named(name)
+		}
+		return routes, nil
+	default:
+		return nil, fmt.Errorf("routes YAML: %s under %q must map to a \"Controller.Action\" string or a map with an %s key", method, displayPath(path), yamlActionKey)
+	}
+}
+
 func displayPath(path string) string {
 	if path == "" {
 		return "/"
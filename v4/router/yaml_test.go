@@ -131,3 +131,213 @@ routes:
 		t.Fatalf("a slash-prefixed path named like a method must stay a path: %v", got)
 	}
 }
+
+func TestParseRoutesYAMLMiddlewareAndStore(t *testing.T) {
+	doc := []byte(`
+routes:
+  /public: Pages.Home
+  /admin:
+    _middleware: [Auth]
+    _store:
+      area: admin
+    /users:
+      _middleware: [Audit]
+      GET: Users.Index
+`)
+	routes, err := router.ParseRoutesYAML(doc)
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("got %d routes, want 2: %s", len(routes), routeSignature(routes))
+	}
+	for _, r := range routes {
+		switch r.Path {
+		case "/public":
+			if len(r.Middleware) != 0 || r.Store != nil {
+				t.Fatalf("%s: unexpected middleware %v or store %v", r.Path, r.Middleware, r.Store)
+			}
+		case "/admin/users":
+			if len(r.Middleware) != 2 || r.Middleware[0].Name != "Auth" || r.Middleware[1].Name != "Audit" {
+				t.Fatalf("%s: middleware %v, want [Auth Audit]", r.Path, r.Middleware)
+			}
+			if r.Store["area"] != "admin" {
+				t.Fatalf("%s: store %v", r.Path, r.Store)
+			}
+		default:
+			t.Fatalf("unexpected route %s", r.Path)
+		}
+	}
+}
+
+func TestParseRoutesYAMLInvalidMiddleware(t *testing.T) {
+	doc := []byte(`
+routes:
+  /admin:
+    _middleware: Auth
+    GET: Admin.Index
+`)
+	if _, err := router.ParseRoutesYAML(doc); err == nil {
+		t.Fatal("expected an error for a non-list middleware key")
+	}
+}
+
+func TestParseRoutesYAMLMethodMap(t *testing.T) {
+	doc := []byte(`
+routes:
+  /users/{id}:
+    GET: { action: Users.Show, middleware: [Auth, RateLimit], store: { scope: read } }
+`)
+	routes, err := router.ParseRoutesYAML(doc)
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	if len(routes) != 1 || routes[0].Controller != "UsersController" || routes[0].Action != "Show" {
+		t.Fatalf("got %s", routeSignature(routes))
+	}
+	r := routes[0]
+	if len(r.Middleware) != 2 || r.Middleware[0].Name != "Auth" || r.Middleware[1].Name != "RateLimit" {
+		t.Fatalf("middleware %v, want [Auth RateLimit]", r.Middleware)
+	}
+	if r.Store["scope"] != "read" {
+		t.Fatalf("store %v", r.Store)
+	}
+}
+
+func TestParseRoutesYAMLMethodMapRequiresAction(t *testing.T) {
+	for _, doc := range []string{
+		"routes:\n  /x:\n    GET: { middleware: [Auth] }\n",
+		"routes:\n  /x:\n    GET: { action: X.Y, actoin: X.Z }\n",
+	} {
+		if _, err := router.ParseRoutesYAML([]byte(doc)); err == nil {
+			t.Fatalf("expected an error for %q", doc)
+		}
+	}
+}
+
+func TestParseRoutesYAMLHost(t *testing.T) {
+	doc := []byte(`
+routes:
+  /admin:
+    _host: Admin.Example.com
+    /users: Users.Index
+  /public: Pages.Home
+`)
+	routes, err := router.ParseRoutesYAML(doc)
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	patterns := make(map[string]bool)
+	for _, r := range routes {
+		patterns[r.Pattern()] = true
+	}
+	for _, want := range []string{"admin.example.com/admin/users", "/public"} {
+		if !patterns[want] {
+			t.Fatalf("missing pattern %q in %v", want, patterns)
+		}
+	}
+}
+
+func TestParseRoutesYAMLName(t *testing.T) {
+	doc := []byte(`
+routes:
+  /users/{id}:
+    GET: { action: Users.Show, name: user }
+`)
+	routes, err := router.ParseRoutesYAML(doc)
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	if len(routes) != 1 || routes[0].Name != "user" {
+		t.Fatalf("got %+v", routes)
+	}
+}
+
+func TestParseRoutesYAMLConstraint(t *testing.T) {
+	doc := []byte(`
+routes:
+  /users/{id:int}:
+    GET: Users.Show
+`)
+	routes, err := router.ParseRoutesYAML(doc)
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	if len(routes) != 1 || routes[0].Path != "/users/{id:int}" {
+		t.Fatalf("got %s", routeSignature(routes))
+	}
+}
+
+func TestParseRoutesYAMLClientCert(t *testing.T) {
+	routes, err := router.ParseRoutesYAML([]byte(`
+routes:
+  /internal:
+    _client_cert: true
+    /whoami: Peers.Whoami
+`))
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	if len(routes) != 1 || len(routes[0].Middleware) != 1 || routes[0].Middleware[0].String() != "ClientCertMiddleware" {
+		t.Fatalf("got %+v", routes)
+	}
+
+	for _, doc := range []string{
+		"routes:\n  /x:\n    _client_cert: false\n    GET: X.Y\n",
+		"routes:\n  /x:\n    _client_cert: { sans: spiffe }\n    GET: X.Y\n",
+		"routes:\n  /x:\n    _client_cert: { subject: [a] }\n    GET: X.Y\n",
+	} {
+		if _, err := router.ParseRoutesYAML([]byte(doc)); err == nil {
+			t.Fatalf("expected an error for %q", doc)
+		}
+	}
+}
+
+func TestParseRoutesYAMLScopeWordsArePaths(t *testing.T) {
+	doc := []byte(`
+routes:
+  store:
+    GET: Store.Index
+  host: Hosts.Index
+  /users: Users.Index
+`)
+	routes, err := router.ParseRoutesYAML(doc)
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	got := routeSet(routes)
+	want := map[string]string{
+		"GET /store": "StoreController.Index",
+		"ANY /host":  "HostsController.Index",
+		"ANY /users": "UsersController.Index",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("route %q: got %q, want %q (all: %v)", k, got[k], v, got)
+		}
+	}
+	for _, r := range routes {
+		if r.Store != nil || r.Host != "" {
+			t.Fatalf("%s: path segments must not leak into scope: store %v, host %q", r.Path, r.Store, r.Host)
+		}
+	}
+}
+
+func TestParseRoutesYAMLUnderscorePaths(t *testing.T) {
+	routes, err := router.ParseRoutesYAML([]byte(`
+routes:
+  _health: Health.Check
+  _internal:
+    GET: Internal.Show
+`))
+	if err != nil {
+		t.Fatalf("ParseRoutesYAML: %v", err)
+	}
+	set := routeSet(routes)
+	if len(set) != 2 || set["ANY /_health"] == "" || set["GET /_internal"] == "" {
+		t.Fatalf("keys starting with _ other than the scope keys should stay paths, got %v", set)
+	}
+}
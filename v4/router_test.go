@@ -2,9 +2,13 @@ package raptor_test
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/core"
 	"github.com/go-raptor/raptor/v4/router"
 )
 
@@ -24,6 +28,30 @@ func (c *RoutesController) Create(ctx *raptor.Context) error {
 	return ctx.Status(http.StatusCreated)
 }
 
+func (c *RoutesController) Moved(ctx *raptor.Context) error {
+	url, err := ctx.URLFor("thing", "id", ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return ctx.Redirect(http.StatusMovedPermanently, url)
+}
+
+func (c *RoutesController) Typed(ctx *raptor.Context) error {
+	n, err := ctx.ParamInt("n")
+	if err != nil {
+		return err
+	}
+	id, err := ctx.ParamUUID("id")
+	if err != nil {
+		return err
+	}
+	d, err := core.ParamAs[time.Duration](ctx, "d")
+	if err != nil {
+		return err
+	}
+	return ctx.Data(map[string]any{"n": n, "id": id, "d": d.String()})
+}
+
 func newRoutesApp(routes router.Routes) *raptor.Raptor {
 	return raptor.NewTestApp(
 		&raptor.Components{Controllers: raptor.Controllers{&RoutesController{}}},
@@ -164,3 +192,239 @@ func TestHeadServedByGet(t *testing.T) {
 		t.Fatalf("HEAD /hello: got %d, want 200", rec.Code)
 	}
 }
+
+func TestAutomaticOptions(t *testing.T) {
+	app := newRoutesApp(router.CollectRoutes(
+		router.Get("/hello", "Routes.Hello"),
+		router.Post("/hello", "Routes.Create"),
+	))
+
+	rec := app.TestRequest(http.MethodOptions, "/hello", nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("OPTIONS /hello: got %d, want 204", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET, HEAD, POST, OPTIONS" {
+		t.Fatalf("Allow header: got %q", allow)
+	}
+	if rec := app.TestRequest(http.MethodOptions, "/missing", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("OPTIONS /missing: got %d, want 404", rec.Code)
+	}
+}
+
+func TestHostRouting(t *testing.T) {
+	app := newRoutesApp(router.CollectRoutes(
+		router.Host("admin.example.com",
+			router.Get("/users/{id}", "Routes.Show"),
+		),
+		router.Get("/hello", "Routes.Hello"),
+		router.Post("/users/{id}", "Routes.Create"),
+	))
+
+	if rec := app.TestGet("/users/7", raptor.WithHost("admin.example.com:8080")); rec.Code != http.StatusOK {
+		t.Fatalf("GET admin /users/7: got %d, want 200", rec.Code)
+	}
+	if rec := app.TestGet("/users/7", raptor.WithHost("Admin.Example.COM")); rec.Code != http.StatusOK {
+		t.Fatalf("GET mixed-case admin host /users/7: got %d, want 200", rec.Code)
+	}
+	if rec := app.TestGet("/hello", raptor.WithHost("admin.example.com")); rec.Code != http.StatusOK {
+		t.Fatalf("GET admin /hello: got %d, want 200 from the host-less route", rec.Code)
+	}
+	if rec := app.TestPost("/users/7", nil, raptor.WithHost("admin.example.com")); rec.Code != http.StatusCreated {
+		t.Fatalf("POST admin /users/7: got %d, want 201 from the host-less route", rec.Code)
+	}
+
+	rec := app.TestGet("/users/7", raptor.WithHost("www.example.com"))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET www /users/7: got %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "POST" {
+		t.Fatalf("GET www /users/7 Allow: got %q, want %q", allow, "POST")
+	}
+
+	rec = app.TestDelete("/users/7", raptor.WithHost("admin.example.com"))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("DELETE admin /users/7: got %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET, HEAD, POST" {
+		t.Fatalf("DELETE admin /users/7 Allow: got %q, want %q", allow, "GET, HEAD, POST")
+	}
+}
+
+func TestHostWithPortFails(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("a host with a port can never match and should fail startup")
+		}
+	}()
+	newRoutesApp(router.Host("api.example.com:8080", router.Get("/hello", "Routes.Hello")))
+}
+
+func TestNamedRouteURL(t *testing.T) {
+	app := newRoutesApp(router.CollectRoutes(
+		router.Get("/things/{id}", "Routes.Show").Named("thing"),
+		router.Get("/files/{path...}", "Routes.Hello").Named("file"),
+		router.Get("/dirs/{name}/{$}", "Routes.Hello").Named("dir"),
+		router.Get("/old/things/{id}", "Routes.Moved"),
+	))
+
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{"thing", []string{"id", "a b/c"}, "/things/a%20b%2Fc"},
+		{"file", []string{"path", "docs/read me.txt"}, "/files/docs/read%20me.txt"},
+		{"file", []string{"path", ""}, "/files/"},
+		{"dir", []string{"name", "x"}, "/dirs/x/"},
+	}
+	for _, tt := range tests {
+		got, err := app.Router.URL(tt.name, tt.params...)
+		if err != nil || got != tt.want {
+			t.Fatalf("URL(%q, %v): got %q, %v; want %q", tt.name, tt.params, got, err, tt.want)
+		}
+	}
+
+	for _, params := range [][]string{nil, {"id"}, {"id", "1", "extra", "2"}, {"id", ""}} {
+		if _, err := app.Router.URL("thing", params...); err == nil {
+			t.Fatalf("URL(thing, %v): expected an error", params)
+		}
+	}
+	if _, err := app.Router.URL("missing"); err == nil {
+		t.Fatal("URL(missing): expected an error")
+	}
+
+	rec := app.TestGet("/old/things/42")
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/things/42" {
+		t.Fatalf("GET /old/things/42: got %d Location %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestDuplicateRouteNameFails(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected startup to fail for a duplicate route name")
+		}
+	}()
+	newRoutesApp(router.CollectRoutes(
+		router.Get("/a", "Routes.Hello").Named("dup"),
+		router.Get("/b", "Routes.Hello").Named("dup"),
+	))
+}
+
+func TestTypedParams(t *testing.T) {
+	app := newRoutesApp(router.Get("/typed/{n}/{id}/{d}", "Routes.Typed"))
+
+	rec := app.TestGet("/typed/7/F47AC10B-58CC-4372-A567-0E02B2C3D479/90s")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"n":7`) || !strings.Contains(body, `"id":"f47ac10b-58cc-4372-a567-0e02b2c3d479"`) || !strings.Contains(body, `"d":"1m30s"`) {
+		t.Fatalf("unexpected body %s", body)
+	}
+
+	rec = app.TestGet("/typed/seven/f47ac10b-58cc-4372-a567-0e02b2c3d479/1s")
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"n":"invalid int`) {
+		t.Fatalf("malformed int: got %d %s", rec.Code, rec.Body.String())
+	}
+	rec = app.TestGet("/typed/7/not-a-uuid/1s")
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"id"`) {
+		t.Fatalf("malformed uuid: got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPathConstraints(t *testing.T) {
+	var calls []string
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{raptor.Use(&TagMiddleware{tag: "mw", log: &calls})},
+		},
+		router.CollectRoutes(
+			router.Get("/things/{id:int}", "Routes.Show").Named("thing"),
+			router.Get("/orders/{id:uuid}", "Routes.Show"),
+		),
+	)
+
+	if rec := app.TestGet("/things/42"); rec.Code != http.StatusOK {
+		t.Fatalf("GET /things/42: got %d", rec.Code)
+	}
+	calls = nil
+	rec := app.TestGet("/things/abc")
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"id":"invalid int`) {
+		t.Fatalf("GET /things/abc: got %d %s", rec.Code, rec.Body.String())
+	}
+	if !slices.Equal(calls, []string{"mw"}) {
+		t.Fatalf("middlewares should observe constraint rejections, got %v", calls)
+	}
+	if rec := app.TestGet("/orders/f47ac10b-58cc-4372-a567-0e02b2c3d479"); rec.Code != http.StatusOK {
+		t.Fatalf("GET /orders/<uuid>: got %d", rec.Code)
+	}
+	if rec := app.TestGet("/orders/42"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("GET /orders/42: got %d, want 400", rec.Code)
+	}
+	if url, err := app.Router.URL("thing", "id", "5"); err != nil || url != "/things/5" {
+		t.Fatalf("URL(thing): got %q, %v", url, err)
+	}
+}
+
+func TestCatchAllPathConstraint(t *testing.T) {
+	app := newRoutesApp(router.Get("/files/{id...:int}", "Routes.Show"))
+
+	if rec := app.TestGet("/files/42"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id":"42"`) {
+		t.Fatalf("GET /files/42: got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := app.TestGet("/files/4/2"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("GET /files/4/2: got %d, want 400", rec.Code)
+	}
+}
+
+func TestUnknownPathConstraintFails(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected startup to fail for an unknown constraint")
+		}
+	}()
+	newRoutesApp(router.Get("/things/{id:nope}", "Routes.Show"))
+}
+
+func TestRoutesTable(t *testing.T) {
+	var calls []string
+	app := raptor.NewTestApp(
+		&raptor.Components{
+			Controllers: raptor.Controllers{&RoutesController{}},
+			Middlewares: raptor.Middlewares{
+				raptor.Use(&TagMiddleware{tag: "global", log: &calls}),
+			},
+		},
+		router.CollectRoutes(
+			router.Get("/hello", "Routes.Hello").Named("hello"),
+			router.Group("/admin",
+				router.WithMiddleware(&DenyMiddleware{}),
+				router.Post("/things", "Routes.Create"),
+			),
+		),
+	)
+
+	table := app.RoutesTable()
+	if len(table) != 2 {
+		t.Fatalf("got %d routes, want 2: %+v", len(table), table)
+	}
+	hello, admin := table[0], table[1]
+	if hello.Method != "GET" || hello.Pattern != "GET /hello" || hello.Name != "hello" || hello.Controller != "RoutesController" || hello.Action != "Hello" {
+		t.Fatalf("hello: %+v", hello)
+	}
+	if !slices.Equal(hello.Middlewares, []string{"TagMiddleware"}) {
+		t.Fatalf("hello middlewares: %v", hello.Middlewares)
+	}
+	if admin.Pattern != "POST /admin/things" || !slices.Equal(admin.Middlewares, []string{"TagMiddleware", "DenyMiddleware"}) {
+		t.Fatalf("admin: %+v", admin)
+	}
+
+	var out strings.Builder
+	if err := router.WriteTable(&out, table); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+	if !strings.Contains(out.String(), "RoutesController.Create") || !strings.Contains(out.String(), "TagMiddleware > DenyMiddleware") {
+		t.Fatalf("unexpected table:\n%s", out.String())
+	}
+}
@@ -0,0 +1,255 @@
+//go:build unix
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Socket activation (sd_listen_fds): listeners are passed as fds 3 and up,
+// LISTEN_FDS counts them, LISTEN_FDNAMES optionally names them, and
+// LISTEN_PID names the process they are meant for.
+const (
+	EnvListenFDs     = "LISTEN_FDS"
+	EnvListenPID     = "LISTEN_PID"
+	EnvListenFDNames = "LISTEN_FDNAMES"
+
+	// EnvReadyFD is set by Reexec to the fd the new process reports
+	// readiness on. Its presence stands in for LISTEN_PID, which the parent
+	// cannot know before the process starts.
+	EnvReadyFD = "RAPTOR_READY_FD"
+
+	// EnvNotifySocket is set by systemd to the socket sd_notify messages
+	// go to.
+	EnvNotifySocket = "NOTIFY_SOCKET"
+)
+
+// RedirectListenerName is the LISTEN_FDNAMES entry that marks an inherited
+// socket as the TLS redirect listener.
+const RedirectListenerName = "redirect"
+
+const listenFDsStart = 3
+
+type namedListener struct {
+	name     string
+	listener net.Listener
+}
+
+// inheritedListeners returns the listeners passed by systemd or by a
+// parent's Reexec, or nil when there are none. The environment variables
+// are cleared so they don't leak into processes this one starts.
+func inheritedListeners() ([]namedListener, error) {
+	count := os.Getenv(EnvListenFDs)
+	pid := os.Getenv(EnvListenPID)
+	_, reexec := os.LookupEnv(EnvReadyFD)
+	if count == "" || (pid != strconv.Itoa(os.Getpid()) && !reexec) {
+		return nil, nil
+	}
+	names := strings.Split(os.Getenv(EnvListenFDNames), ":")
+	os.Unsetenv(EnvListenFDs)
+	os.Unsetenv(EnvListenPID)
+	os.Unsetenv(EnvListenFDNames)
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid %s %q", EnvListenFDs, count)
+	}
+	listeners := make([]namedListener, 0, n)
+	for i := range n {
+		fd := listenFDsStart + i
+		syscall.CloseOnExec(fd)
+		file := os.NewFile(uintptr(fd), "inherited-listener-"+strconv.Itoa(fd))
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.listener.Close()
+			}
+			return nil, fmt.Errorf("inherited fd %d: %w", fd, err)
+		}
+		var name string
+		if i < len(names) {
+			name = names[i]
+		}
+		listeners = append(listeners, namedListener{name: name, listener: listener})
+	}
+	return listeners, nil
+}
+
+// notifyReady tells the process that started this one with Reexec that it
+// is listening.
+func notifyReady() {
+	value, ok := os.LookupEnv(EnvReadyFD)
+	if !ok {
+		return
+	}
+	os.Unsetenv(EnvReadyFD)
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+	ready := os.NewFile(uintptr(fd), "ready")
+	ready.Write([]byte{1}) //nolint:errcheck // the parent treats EOF as failure
+	ready.Close()
+}
+
+// Reexec starts a new instance of the running executable with the same
+// arguments and environment, hands it the bound listeners, and waits up to
+// timeout for it to report that it is listening. On success the caller
+// drains this process, e.g. with Raptor.Shutdown; meanwhile new connections
+// queue on the shared sockets, so none are refused. On failure the new
+// process is killed and this one keeps serving. It returns the new
+// process's pid.
+//
+// Under systemd (NOTIFY_SOCKET set) it tells the service manager that the
+// new process is the unit's main process with MAINPID=, so the unit
+// survives this one exiting. systemd only accepts that from the main
+// process with NotifyAccess=main or all, which Type=notify units get by
+// default; other units need NotifyAccess set, or re-exec must run outside
+// systemd supervision. If the message can't be sent, the restart fails.
+func (s *Server) Reexec(timeout time.Duration) (int, error) {
+	if s.listeners == nil {
+		return 0, errors.New("reexec: server is not listening")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("reexec: %w", err)
+	}
+
+	var files []*os.File
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	names := make([]string, 0, len(s.listeners)+1)
+	for _, listener := range s.listeners {
+		file, err := listenerFile(listener)
+		if err != nil {
+			return 0, err
+		}
+		files = append(files, file)
+		names = append(names, "listener")
+	}
+	if s.redirectListener != nil {
+		file, err := listenerFile(s.redirectListener)
+		if err != nil {
+			return 0, err
+		}
+		files = append(files, file)
+		names = append(names, RedirectListenerName)
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return 0, fmt.Errorf("reexec: %w", err)
+	}
+	defer readyR.Close()
+	files = append(files, readyW)
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		EnvListenFDs+"="+strconv.Itoa(len(names)),
+		EnvListenFDNames+"="+strings.Join(names, ":"),
+		EnvReadyFD+"="+strconv.Itoa(listenFDsStart+len(names)),
+	)
+	err = cmd.Start()
+	// Handing fds to the new process puts the sockets, shared with this
+	// process's listeners, into blocking mode; accepting on a blocking
+	// socket would make closing the listener hang.
+	for _, listener := range s.listeners {
+		setNonblock(listener)
+	}
+	setNonblock(s.redirectListener)
+	if err != nil {
+		return 0, fmt.Errorf("reexec: %w", err)
+	}
+	// Only the child holds the write end now, so a crash reads as EOF.
+	readyW.Close()
+	files = files[:len(files)-1]
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := readyR.Read(make([]byte, 1))
+		ready <- err
+	}()
+	select {
+	case err = <-ready:
+		if err != nil {
+			err = errors.New("reexec: new process exited before it was listening")
+		}
+	case <-time.After(timeout):
+		err = fmt.Errorf("reexec: new process not listening after %s", timeout)
+	}
+	if err == nil {
+		if err = notifySystemd("MAINPID=" + strconv.Itoa(cmd.Process.Pid)); err != nil {
+			err = fmt.Errorf("reexec: notify systemd: %w", err)
+		}
+	}
+	if err != nil {
+		cmd.Process.Kill() //nolint:errcheck // it may have exited already
+		cmd.Wait()         //nolint:errcheck // reaped; the failure is err
+		return 0, err
+	}
+
+	// The socket files now belong to the new process.
+	for _, listener := range s.listeners {
+		keepSocketFile(listener)
+	}
+	keepSocketFile(s.redirectListener)
+	pid := cmd.Process.Pid
+	cmd.Process.Release() //nolint:errcheck // the new process outlives this one
+	return pid, nil
+}
+
+// notifySystemd sends state to the service manager (sd_notify), doing
+// nothing outside systemd.
+func notifySystemd(state string) error {
+	socket := os.Getenv(EnvNotifySocket)
+	if socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+func setNonblock(listener net.Listener) {
+	if sc, ok := listener.(syscall.Conn); ok {
+		if raw, err := sc.SyscallConn(); err == nil {
+			raw.Control(func(fd uintptr) { syscall.SetNonblock(int(fd), true) }) //nolint:errcheck // best effort
+		}
+	}
+}
+
+func keepSocketFile(listener net.Listener) {
+	if unix, ok := listener.(*net.UnixListener); ok {
+		unix.SetUnlinkOnClose(false)
+	}
+}
+
+func listenerFile(listener net.Listener) (*os.File, error) {
+	filer, ok := listener.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("reexec: cannot hand over %T listener", listener)
+	}
+	file, err := filer.File()
+	if err != nil {
+		return nil, fmt.Errorf("reexec: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,30 @@
+//go:build !unix
+
+package server
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+type namedListener struct {
+	name     string
+	listener net.Listener
+}
+
+// RedirectListenerName is the LISTEN_FDNAMES entry that marks an inherited
+// socket as the TLS redirect listener.
+const RedirectListenerName = "redirect"
+
+// Socket activation is only available on unix.
+func inheritedListeners() ([]namedListener, error) {
+	return nil, nil
+}
+
+func notifyReady() {}
+
+// Reexec is only available on unix.
+func (s *Server) Reexec(timeout time.Duration) (int, error) {
+	return 0, errors.New("reexec: not supported on this platform")
+}
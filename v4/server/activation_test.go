@@ -0,0 +1,168 @@
+//go:build unix
+
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-raptor/raptor/v4/config"
+)
+
+// envActivationChild turns the test binary into the process the activation
+// tests fork: it serves its pid on the listeners it inherits until killed.
+// With the value "fail" it exits before listening.
+const envActivationChild = "RAPTOR_TEST_ACTIVATION_CHILD"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(envActivationChild); mode != "" {
+		runActivationChild(mode)
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func runActivationChild(mode string) {
+	if mode == "fail" {
+		os.Exit(1)
+	}
+	s := pidServer(config.ListenerConfig{Address: "127.0.0.1:0"})
+	if err := s.Listen(); err != nil {
+		os.Exit(2)
+	}
+	s.Serve() //nolint:errcheck // runs until the test kills the process
+	os.Exit(0)
+}
+
+// pidServer answers every request with the pid of the process serving it.
+func pidServer(listeners ...config.ListenerConfig) *Server {
+	s := listenersServer(listeners...)
+	s.server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Connection", "close")
+		io.WriteString(w, strconv.Itoa(os.Getpid()))
+	})
+	return s
+}
+
+func TestSocketActivation(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := listener.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Like systemd, set LISTEN_PID to the pid of the process that gets the
+	// fds: the shell's, which exec keeps for the test binary.
+	cmd := exec.Command("/bin/sh", "-c", `LISTEN_PID=$$ exec "$0"`, os.Args[0])
+	cmd.ExtraFiles = []*os.File{file}
+	cmd.Env = append(os.Environ(), envActivationChild+"=serve", EnvListenFDs+"=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+	file.Close()
+	listener.Close()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if got := getBody(t, client, "http://"+listener.Addr().String()+"/"); got != strconv.Itoa(cmd.Process.Pid) {
+		t.Fatalf("the activated process should serve the passed socket: got pid %s, want %d", got, cmd.Process.Pid)
+	}
+}
+
+func TestInheritedListenersIgnoresOtherPID(t *testing.T) {
+	t.Setenv(EnvListenFDs, "1")
+	t.Setenv(EnvListenPID, strconv.Itoa(os.Getpid()+1))
+
+	listeners, err := inheritedListeners()
+	if err != nil || listeners != nil {
+		t.Fatalf("fds meant for another process must be ignored: %v, %v", listeners, err)
+	}
+	if os.Getenv(EnvListenFDs) != "1" {
+		t.Fatal("the environment should be left alone")
+	}
+}
+
+func TestReexecHandsOverListeners(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	s := pidServer(
+		config.ListenerConfig{Address: "127.0.0.1:0"},
+		config.ListenerConfig{Network: "unix", Address: socket},
+	)
+	if err := s.Listen(); err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	go s.Serve() //nolint:errcheck // returns on Shutdown
+	addr := s.Addresses()[0]
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if got := getBody(t, client, "http://"+addr+"/"); got != strconv.Itoa(os.Getpid()) {
+		t.Fatalf("before reexec: got pid %s", got)
+	}
+
+	notifySocket := filepath.Join(t.TempDir(), "notify.sock")
+	notify, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: notifySocket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer notify.Close()
+	t.Setenv(EnvNotifySocket, notifySocket)
+
+	t.Setenv(envActivationChild, "serve")
+	pid, err := s.Reexec(10 * time.Second)
+	if err != nil {
+		t.Fatalf("Reexec: %v", err)
+	}
+	defer syscall.Kill(pid, syscall.SIGKILL)
+
+	message := make([]byte, 64)
+	notify.SetReadDeadline(time.Now().Add(10 * time.Second))
+	n, err := notify.Read(message)
+	if err != nil || string(message[:n]) != "MAINPID="+strconv.Itoa(pid) {
+		t.Fatalf("systemd should be told the new main pid: got %q, %v", message[:n], err)
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	want := strconv.Itoa(pid)
+	if got := getBody(t, client, "http://"+addr+"/"); got != want {
+		t.Fatalf("tcp after reexec: got pid %s, want %s", got, want)
+	}
+	if got := getBody(t, unixClient(socket), "http://unix/"); got != want {
+		t.Fatalf("unix socket after reexec: got pid %s, want %s", got, want)
+	}
+}
+
+func TestReexecFailureKeepsServing(t *testing.T) {
+	s := pidServer(config.ListenerConfig{Address: "127.0.0.1:0"})
+	if err := s.Listen(); err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer s.Close()
+	go s.Serve() //nolint:errcheck // returns on Close
+
+	t.Setenv(envActivationChild, "fail")
+	if _, err := s.Reexec(10 * time.Second); err == nil {
+		t.Fatal("Reexec should fail when the new process exits before listening")
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if got := getBody(t, client, "http://"+s.Address()+"/"); got != strconv.Itoa(os.Getpid()) {
+		t.Fatalf("the old process should keep serving: got pid %s", got)
+	}
+}
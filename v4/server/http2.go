@@ -0,0 +1,59 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/go-raptor/raptor/v4/config"
+)
+
+// HTTP/2 limits net/http would otherwise silently replace with defaults:
+// the frame size bounds of RFC 9113, the initial flow-control window as the
+// smallest connection buffer, and 2^31-1 as the largest window.
+const (
+	minReadFrameSize    = 16 << 10
+	maxReadFrameSize    = 16<<20 - 1
+	minConnectionBuffer = 65535
+	maxReceiveBuffer    = math.MaxInt32
+)
+
+// configureHTTP2 applies cfg to server: the HTTP/2 limits, and unencrypted
+// HTTP/2 alongside HTTP/1.1 when H2C is set.
+func configureHTTP2(server *http.Server, cfg config.HTTP2Config) error {
+	if err := validateHTTP2(cfg); err != nil {
+		return err
+	}
+	server.HTTP2 = &http.HTTP2Config{
+		MaxConcurrentStreams:          cfg.MaxConcurrentStreams,
+		MaxReadFrameSize:              cfg.MaxReadFrameSize,
+		MaxReceiveBufferPerConnection: cfg.MaxReceiveBufferPerConnection,
+		MaxReceiveBufferPerStream:     cfg.MaxReceiveBufferPerStream,
+		SendPingTimeout:               seconds(cfg.SendPingTimeout),
+		PingTimeout:                   seconds(cfg.PingTimeout),
+	}
+	if cfg.H2C {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		server.Protocols = protocols
+	}
+	return nil
+}
+
+func validateHTTP2(cfg config.HTTP2Config) error {
+	switch {
+	case cfg.MaxConcurrentStreams < 0:
+		return fmt.Errorf("http2: max_concurrent_streams must not be negative")
+	case cfg.MaxReadFrameSize != 0 && (cfg.MaxReadFrameSize < minReadFrameSize || cfg.MaxReadFrameSize > maxReadFrameSize):
+		return fmt.Errorf("http2: max_read_frame_size %d must be between %d and %d", cfg.MaxReadFrameSize, minReadFrameSize, maxReadFrameSize)
+	case cfg.MaxReceiveBufferPerConnection != 0 && (cfg.MaxReceiveBufferPerConnection < minConnectionBuffer || cfg.MaxReceiveBufferPerConnection > maxReceiveBuffer):
+		return fmt.Errorf("http2: max_receive_buffer_per_connection %d must be between %d and %d", cfg.MaxReceiveBufferPerConnection, minConnectionBuffer, maxReceiveBuffer)
+	case cfg.MaxReceiveBufferPerStream < 0 || cfg.MaxReceiveBufferPerStream > maxReceiveBuffer:
+		return fmt.Errorf("http2: max_receive_buffer_per_stream %d must be between 1 and %d", cfg.MaxReceiveBufferPerStream, maxReceiveBuffer)
+	case cfg.SendPingTimeout < 0 || cfg.PingTimeout < 0:
+		return fmt.Errorf("http2: ping timeouts must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-raptor/raptor/v4/config"
+)
+
+func http2Server(t *testing.T, http2 config.HTTP2Config) *Server {
+	t.Helper()
+	cfg := config.NewConfigDefaults().ServerConfig
+	cfg.Port = 0
+	cfg.HTTP2 = http2
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	})
+	s := NewServer(&cfg, mux, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := s.Listen(); err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	go s.Serve() //nolint:errcheck // returns on Close
+	return s
+}
+
+func protoClient(h2c bool) *http.Client {
+	protocols := new(http.Protocols)
+	if h2c {
+		protocols.SetUnencryptedHTTP2(true)
+	} else {
+		protocols.SetHTTP1(true)
+	}
+	return &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{Protocols: protocols}}
+}
+
+func TestH2C(t *testing.T) {
+	s := http2Server(t, config.HTTP2Config{H2C: true, MaxConcurrentStreams: 50, MaxReadFrameSize: 1 << 20, PingTimeout: 5})
+
+	for _, tc := range []struct {
+		h2c  bool
+		want string
+	}{
+		{true, "HTTP/2.0"},
+		{false, "HTTP/1.1"},
+	} {
+		if got := getBody(t, protoClient(tc.h2c), "http://"+s.Address()+"/"); got != tc.want {
+			t.Fatalf("h2c client %v: got %s, want %s", tc.h2c, got, tc.want)
+		}
+	}
+
+	h2 := s.server.HTTP2
+	if h2.MaxConcurrentStreams != 50 || h2.MaxReadFrameSize != 1<<20 || h2.PingTimeout != 5*time.Second {
+		t.Fatalf("HTTP2 settings: got %+v", h2)
+	}
+}
+
+func TestH2CDisabledByDefault(t *testing.T) {
+	s := http2Server(t, config.HTTP2Config{})
+
+	resp, err := protoClient(true).Get("http://" + s.Address() + "/")
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("unencrypted HTTP/2 should be refused without h2c, got %s", resp.Proto)
+	}
+}
+
+func TestListenRejectsInvalidHTTP2Settings(t *testing.T) {
+	for name, http2 := range map[string]config.HTTP2Config{
+		"frame size too small": {MaxReadFrameSize: 1024},
+		"frame size too large": {MaxReadFrameSize: 16 << 20},
+		"negative streams":     {MaxConcurrentStreams: -1},
+		"connection buffer":    {MaxReceiveBufferPerConnection: 1024},
+		"stream buffer":        {MaxReceiveBufferPerStream: -1},
+		"connection too large": {MaxReceiveBufferPerConnection: 1 << 31},
+		"ping timeout":         {PingTimeout: -1},
+	} {
+		s := testServer(0)
+		s.http2 = http2
+		if err := s.Listen(); err == nil {
+			s.Close()
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestValidateHTTP2AcceptsNetHTTPBounds(t *testing.T) {
+	for _, cfg := range []config.HTTP2Config{
+		{MaxReceiveBufferPerConnection: 8 << 20, MaxReceiveBufferPerStream: 8 << 20},
+		{MaxReceiveBufferPerConnection: 65535, MaxReceiveBufferPerStream: 1},
+		{MaxReceiveBufferPerConnection: 1<<31 - 1, MaxReceiveBufferPerStream: 1<<31 - 1},
+	} {
+		if err := validateHTTP2(cfg); err != nil {
+			t.Errorf("%+v: %v", cfg, err)
+		}
+	}
+}
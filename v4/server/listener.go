@@ -0,0 +1,77 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+var listenerNetworks = []string{"tcp", "tcp4", "tcp6", "unix"}
+
+// listen binds one configured listener.
+func listen(cfg config.ListenerConfig) (net.Listener, error) {
+	network := cfg.Network
+	if network == "" {
+		network = "tcp"
+	}
+	if !slices.Contains(listenerNetworks, network) {
+		return nil, fmt.Errorf("%w: %q (expected tcp, tcp4, tcp6, or unix)", errs.ErrInvalidListenerNetwork, cfg.Network)
+	}
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("%s listener: address is required", network)
+	}
+	if network != "unix" {
+		if cfg.Permissions != "" {
+			return nil, fmt.Errorf("%s listener %s: permissions apply only to unix sockets", network, cfg.Address)
+		}
+		return net.Listen(network, cfg.Address)
+	}
+	return listenUnix(cfg.Address, cfg.Permissions)
+}
+
+func listenUnix(path, permissions string) (net.Listener, error) {
+	var mode os.FileMode
+	if permissions != "" {
+		m, err := strconv.ParseUint(permissions, 8, 32)
+		if err != nil || m > 0o777 {
+			return nil, fmt.Errorf("unix listener %s: invalid permissions %q (expected octal, e.g. 0660)", path, permissions)
+		}
+		mode = os.FileMode(m)
+	}
+
+	removeStaleSocket(path)
+	if permissions == "" {
+		return net.Listen("unix", path)
+	}
+	return listenUnixMode(path, mode)
+}
+
+// removeStaleSocket deletes a socket file left behind by a process that
+// exited without closing its listener, unless something still accepts
+// connections on it.
+func removeStaleSocket(path string) {
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return
+	}
+	os.Remove(path)
+}
+
+// listenerAddress formats a bound listener's address, prefixing unix
+// socket paths with "unix:".
+func listenerAddress(l net.Listener) string {
+	addr := l.Addr()
+	if addr.Network() == "unix" {
+		return "unix:" + addr.String()
+	}
+	return addr.String()
+}
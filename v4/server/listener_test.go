@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+func listenersServer(listeners ...config.ListenerConfig) *Server {
+	cfg := config.NewConfigDefaults().ServerConfig
+	cfg.Listeners = listeners
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	})
+	return NewServer(&cfg, mux, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func unixClient(path string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+}
+
+func TestMultipleListeners(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	s := listenersServer(
+		config.ListenerConfig{Network: "unix", Address: socket, Permissions: "0660"},
+		config.ListenerConfig{Address: "127.0.0.1:0"},
+		config.ListenerConfig{Network: "tcp4", Address: "127.0.0.1:0"},
+	)
+	if err := s.Listen(); err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer s.Close()
+	go s.Serve() //nolint:errcheck // returns on Close
+
+	addresses := s.Addresses()
+	if len(addresses) != 3 || addresses[0] != "unix:"+socket {
+		t.Fatalf("Addresses: got %v", addresses)
+	}
+	if got := s.Address(); got != strings.Join(addresses, ", ") {
+		t.Fatalf("Address should report every listener: %q", got)
+	}
+
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o660 {
+		t.Fatalf("socket permissions: got %o, want 660", perm)
+	}
+
+	resp, err := unixClient(socket).Get("http://unix/")
+	if err != nil {
+		t.Fatalf("GET over unix socket: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unix socket: got %d", resp.StatusCode)
+	}
+	for _, addr := range addresses[1:] {
+		resp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			t.Fatalf("GET %s: %v", addr, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: got %d", addr, resp.StatusCode)
+		}
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Fatalf("socket file should be removed on shutdown: %v", err)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	s := listenersServer(config.ListenerConfig{Network: "unix", Address: socket})
+	if err := s.Listen(); err != nil {
+		t.Fatalf("a stale socket file should not block Listen: %v", err)
+	}
+	s.Close()
+}
+
+func TestListenRejectsInvalidListeners(t *testing.T) {
+	for name, cfg := range map[string]config.ListenerConfig{
+		"network":           {Network: "udp", Address: "127.0.0.1:0"},
+		"missing address":   {Network: "tcp"},
+		"tcp permissions":   {Address: "127.0.0.1:0", Permissions: "0660"},
+		"octal permissions": {Network: "unix", Address: filepath.Join(t.TempDir(), "a.sock"), Permissions: "rw"},
+	} {
+		s := listenersServer(config.ListenerConfig{Address: "127.0.0.1:0"}, cfg)
+		if err := s.Listen(); err == nil {
+			s.Close()
+			t.Errorf("%s: expected an error", name)
+		} else if s.listeners != nil {
+			t.Errorf("%s: listeners bound before the failure should be closed", name)
+		}
+	}
+
+	s := listenersServer(config.ListenerConfig{Network: "udp", Address: "127.0.0.1:0"})
+	if err := s.Listen(); !errors.Is(err, errs.ErrInvalidListenerNetwork) {
+		t.Fatalf("got %v, want ErrInvalidListenerNetwork", err)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package server
+
+import (
+	"net"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// unixSocketUmask serializes umask changes within the package.
+var unixSocketUmask sync.Mutex
+
+// listenUnixMode binds a unix socket that is created with mode. Changing
+// the mode after bind would leave a window in which the socket is reachable
+// with the default permissions, so the umask is narrowed around bind
+// instead. The umask is process-wide: files other goroutines create during
+// the bind get at most mode's permissions, which only errs on the strict
+// side, and listeners are bound during startup.
+func listenUnixMode(path string, mode os.FileMode) (net.Listener, error) {
+	unixSocketUmask.Lock()
+	defer unixSocketUmask.Unlock()
+	old := syscall.Umask(int(0o777 &^ mode))
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
@@ -6,21 +6,37 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-raptor/raptor/v4/config"
 )
 
 type Server struct {
-	server   *http.Server
-	listener net.Listener
+	server    *http.Server
+	configs   []config.ListenerConfig
+	listeners []net.Listener
+	tls       config.TLSConfig
+	http2     config.HTTP2Config
+
+	redirect         *http.Server
+	redirectListener net.Listener
 }
 
-func NewServer(cfg *config.ServerConfig, mux *http.ServeMux, log *slog.Logger) *Server {
+func NewServer(cfg *config.ServerConfig, handler http.Handler, log *slog.Logger) *Server {
+	addr := fmt.Sprintf("%s:%d", cfg.Address, cfg.Port)
+	configs := cfg.Listeners
+	if len(configs) == 0 {
+		configs = []config.ListenerConfig{{Network: "tcp", Address: addr}}
+	}
 	return &Server{
+		configs: configs,
+		tls:     cfg.TLS,
+		http2:   cfg.HTTP2,
 		server: &http.Server{
-			Addr:              fmt.Sprintf("%s:%d", cfg.Address, cfg.Port),
-			Handler:           mux,
+			Addr:              addr,
+			Handler:           handler,
 			ReadTimeout:       seconds(cfg.ReadTimeout),
 			ReadHeaderTimeout: seconds(cfg.ReadHeaderTimeout),
 			WriteTimeout:      seconds(cfg.WriteTimeout),
@@ -38,26 +54,121 @@ func seconds(n int) time.Duration {
 	return time.Duration(n) * time.Second
 }
 
-// Listen binds the configured address without serving yet, so bind
+// Listen binds every configured listener without serving yet, so bind
 // errors surface synchronously before the app reports itself running.
+// Listeners inherited through socket activation (LISTEN_FDS) or from
+// Reexec are used instead of binding the configured ones. With TLS
+// configured it also loads the certificates and binds the redirect
+// listener, if any. Invalid HTTP/2 settings fail here too.
 func (s *Server) Listen() error {
-	listener, err := net.Listen("tcp", s.server.Addr)
+	if err := configureHTTP2(s.server, s.http2); err != nil {
+		return err
+	}
+	if s.tls.Enabled() && s.server.TLSConfig == nil {
+		tlsConfig, err := newTLSConfig(s.tls)
+		if err != nil {
+			return err
+		}
+		s.server.TLSConfig = tlsConfig
+	}
+
+	inherited, err := inheritedListeners()
 	if err != nil {
 		return err
 	}
-	s.listener = listener
+	var redirect net.Listener
+	for _, l := range inherited {
+		if l.name == RedirectListenerName {
+			redirect = l.listener
+		} else {
+			s.listeners = append(s.listeners, l.listener)
+		}
+	}
+	if len(inherited) == 0 {
+		for _, cfg := range s.configs {
+			listener, err := listen(cfg)
+			if err != nil {
+				s.closeListeners()
+				return err
+			}
+			s.listeners = append(s.listeners, listener)
+		}
+	}
+
+	if s.server.TLSConfig != nil && (s.tls.RedirectAddress != "" || redirect != nil) {
+		if err := s.listenRedirect(redirect); err != nil {
+			s.closeListeners()
+			return err
+		}
+	} else if redirect != nil {
+		redirect.Close()
+	}
+
+	notifyReady()
+	return nil
+}
+
+func (s *Server) closeListeners() {
+	for _, listener := range s.listeners {
+		listener.Close()
+	}
+	s.listeners = nil
+}
+
+// listenRedirect serves the redirect to HTTPS on listener, binding
+// RedirectAddress when it is nil.
+func (s *Server) listenRedirect(listener net.Listener) error {
+	if listener == nil {
+		var err error
+		if listener, err = net.Listen("tcp", s.tls.RedirectAddress); err != nil {
+			return fmt.Errorf("tls redirect listener: %w", err)
+		}
+	}
+	s.redirectListener = listener
+	s.redirect = &http.Server{
+		Handler:           redirectHandler(s.httpsPort()),
+		ReadHeaderTimeout: s.server.ReadHeaderTimeout,
+		IdleTimeout:       s.server.IdleTimeout,
+		ErrorLog:          s.server.ErrorLog,
+	}
 	return nil
 }
 
-// Serve accepts connections on the bound listener, binding first if
-// Listen has not been called.
+// httpsPort is the port of the first TCP listener, which the redirect
+// listener points clients at.
+func (s *Server) httpsPort() string {
+	for _, listener := range s.listeners {
+		if addr, ok := listener.Addr().(*net.TCPAddr); ok {
+			return strconv.Itoa(addr.Port)
+		}
+	}
+	return ""
+}
+
+// Serve accepts connections on every bound listener, binding first if
+// Listen has not been called. It returns when the first listener stops,
+// which after Shutdown or Close is http.ErrServerClosed.
 func (s *Server) Serve() error {
-	if s.listener == nil {
+	if s.listeners == nil {
 		if err := s.Listen(); err != nil {
 			return err
 		}
 	}
-	return s.server.Serve(s.listener)
+	if s.redirect != nil {
+		go s.redirect.Serve(s.redirectListener) //nolint:errcheck // returns on Shutdown or Close
+	}
+
+	// Decided up front: serving the first listener fills in TLSConfig
+	// for HTTP/2 even without TLS.
+	serve := s.server.Serve
+	if s.server.TLSConfig != nil {
+		serve = func(l net.Listener) error { return s.server.ServeTLS(l, "", "") }
+	}
+	errc := make(chan error, len(s.listeners))
+	for _, listener := range s.listeners {
+		go func() { errc <- serve(listener) }()
+	}
+	return <-errc
 }
 
 func (s *Server) Start() error {
@@ -65,18 +176,51 @@ func (s *Server) Start() error {
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.redirect != nil {
+		s.redirect.Shutdown(ctx) //nolint:errcheck // the main server's error matters
+	}
 	return s.server.Shutdown(ctx)
 }
 
 func (s *Server) Close() error {
+	if s.redirect != nil {
+		s.redirect.Close() //nolint:errcheck // the main server's error matters
+	}
 	return s.server.Close()
 }
 
-// Address returns the bound address once listening (reporting the real
-// port when the config asked for :0), or the configured address before.
+// Address returns the bound addresses once listening, comma-separated
+// (reporting the real port when the config asked for :0), or the configured
+// ones before.
 func (s *Server) Address() string {
-	if s.listener != nil {
-		return s.listener.Addr().String()
+	return strings.Join(s.Addresses(), ", ")
+}
+
+// Addresses returns the address of each listener, in configuration order.
+// Unix socket paths are prefixed with "unix:".
+func (s *Server) Addresses() []string {
+	addresses := make([]string, 0, len(s.configs))
+	if s.listeners != nil {
+		for _, listener := range s.listeners {
+			addresses = append(addresses, listenerAddress(listener))
+		}
+		return addresses
+	}
+	for _, cfg := range s.configs {
+		if cfg.Network == "unix" {
+			addresses = append(addresses, "unix:"+cfg.Address)
+		} else {
+			addresses = append(addresses, cfg.Address)
+		}
+	}
+	return addresses
+}
+
+// RedirectAddress returns the bound address of the plain-HTTP redirect
+// listener, or "" when there is none.
+func (s *Server) RedirectAddress() string {
+	if s.redirectListener != nil {
+		return s.redirectListener.Addr().String()
 	}
-	return s.server.Addr
+	return ""
 }
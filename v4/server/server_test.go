@@ -18,6 +18,20 @@ func testServer(port int) *Server {
 	return NewServer(&cfg, http.NewServeMux(), logger)
 }
 
+func getBody(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	return string(body)
+}
+
 func TestNewServerConfiguresErrorLogAndTimeouts(t *testing.T) {
 	s := testServer(0)
 
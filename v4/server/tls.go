@@ -0,0 +1,211 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/errs"
+)
+
+// certCheckInterval bounds how often handshakes stat the certificate files
+// for changes.
+var certCheckInterval = time.Second
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// newTLSConfig builds the server's TLS configuration from cfg.
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	if cfg.Cert == "" || cfg.Key == "" {
+		return nil, fmt.Errorf("tls: both cert and key must be set")
+	}
+	reloader, err := newCertReloader(cfg.Cert, cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if cfg.MinVersion != "" {
+		version, ok := tlsVersions[strings.TrimPrefix(cfg.MinVersion, "TLS")]
+		if !ok {
+			return nil, fmt.Errorf("tls: invalid min_version %q (expected 1.0, 1.1, 1.2, or 1.3)", cfg.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if len(cfg.CipherSuites) > 0 {
+		byName := make(map[string]uint16)
+		for _, suite := range tls.CipherSuites() {
+			byName[suite.Name] = suite.ID
+		}
+		for _, name := range cfg.CipherSuites {
+			id, ok := byName[strings.TrimSpace(name)]
+			if !ok {
+				return nil, fmt.Errorf("tls: unknown or insecure cipher suite %q", name)
+			}
+			tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+		}
+	}
+
+	if cfg.ClientCA != "" {
+		pem, err := readPEM(cfg.ClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("tls: client_ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls: client_ca holds no PEM certificates")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if cfg.ClientAuth != "" {
+		auth, ok := clientAuthTypes[strings.ToLower(cfg.ClientAuth)]
+		if !ok {
+			return nil, fmt.Errorf("tls: invalid client_auth %q", cfg.ClientAuth)
+		}
+		if auth >= tls.VerifyClientCertIfGiven && tlsConfig.ClientCAs == nil {
+			return nil, fmt.Errorf("tls: client_auth %q requires client_ca", cfg.ClientAuth)
+		}
+		tlsConfig.ClientAuth = auth
+	}
+	return tlsConfig, nil
+}
+
+// isPEM reports whether a cert, key, or CA setting holds inline PEM rather
+// than a file path.
+func isPEM(value string) bool {
+	return strings.Contains(value, "-----BEGIN")
+}
+
+func readPEM(value string) ([]byte, error) {
+	if isPEM(value) {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// certReloader serves the key pair from cert and key, reloading it when the
+// files' modification times change. Inline PEM never changes.
+type certReloader struct {
+	certPath, keyPath string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func newCertReloader(cert, key string) (*certReloader, error) {
+	if isPEM(cert) != isPEM(key) {
+		return nil, fmt.Errorf("tls: cert and key must both be files or both be inline PEM: %w", errs.ErrInvalidCertOrKeyType)
+	}
+	if isPEM(cert) {
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		return &certReloader{cert: &pair}, nil
+	}
+	r := &certReloader{certPath: cert, keyPath: key}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if r.certPath != "" {
+		r.maybeReload()
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *certReloader) maybeReload() {
+	r.mu.RLock()
+	due := time.Since(r.checkedAt) >= certCheckInterval
+	r.mu.RUnlock()
+	if !due {
+		return
+	}
+	// A failed reload keeps serving the previous certificate; the next
+	// check retries, e.g. once both files of a rotation are in place.
+	_ = r.reload()
+}
+
+func (r *certReloader) reload() error {
+	modTime, err := latestModTime(r.certPath, r.keyPath)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkedAt = time.Now()
+	if r.cert != nil && modTime.Equal(r.modTime) {
+		return nil
+	}
+	pair, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+	r.cert, r.modTime = &pair, modTime
+	return nil
+}
+
+func latestModTime(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// redirectHandler sends plain-HTTP requests to the same host and URI over
+// HTTPS on httpsPort, omitting the port when it is 443.
+func redirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host += ":" + httpsPort
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-raptor/raptor/v4/config"
+)
+
+// writeCert writes a self-signed certificate for 127.0.0.1 with the given
+// common name to cert.pem and key.pem in dir.
+func writeCert(t *testing.T, dir, commonName string) (certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath, keyPath = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+func tlsTestServer(t *testing.T, tlsConfig config.TLSConfig) *Server {
+	t.Helper()
+	s := testServer(0)
+	s.tls = tlsConfig
+	s.server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if err := s.Listen(); err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	go s.Serve() //nolint:errcheck // returns on Close
+	return s
+}
+
+// peerCommonName connects to addr and returns the common name of the
+// certificate the server presents.
+func peerCommonName(t *testing.T, addr string) string {
+	t.Helper()
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("tls dial %s: %v", addr, err)
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
+
+func TestServeTLS(t *testing.T) {
+	cert, key := writeCert(t, t.TempDir(), "first")
+	s := tlsTestServer(t, config.TLSConfig{Cert: cert, Key: key})
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := client.Get("https://" + s.Address() + "/")
+	if err != nil {
+		t.Fatalf("GET over TLS: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("status: got %d", resp.StatusCode)
+	}
+	if resp.TLS == nil || resp.TLS.Version < tls.VersionTLS12 {
+		t.Fatalf("expected at least TLS 1.2, got %+v", resp.TLS)
+	}
+}
+
+func TestServeTLSReloadsRotatedCertificate(t *testing.T) {
+	defer func(interval time.Duration) { certCheckInterval = interval }(certCheckInterval)
+	certCheckInterval = 0
+
+	dir := t.TempDir()
+	cert, key := writeCert(t, dir, "first")
+	s := tlsTestServer(t, config.TLSConfig{Cert: cert, Key: key})
+
+	if cn := peerCommonName(t, s.Address()); cn != "first" {
+		t.Fatalf("initial certificate: got %q", cn)
+	}
+
+	writeCert(t, dir, "second")
+	// Some filesystems have coarse mtimes; make the rotation observable.
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(cert, later, later)
+	os.Chtimes(key, later, later)
+
+	if cn := peerCommonName(t, s.Address()); cn != "second" {
+		t.Fatalf("rotated certificate was not picked up: got %q", cn)
+	}
+}
+
+func TestServeTLSKeepsCertificateWhenReloadFails(t *testing.T) {
+	defer func(interval time.Duration) { certCheckInterval = interval }(certCheckInterval)
+	certCheckInterval = 0
+
+	cert, key := writeCert(t, t.TempDir(), "first")
+	s := tlsTestServer(t, config.TLSConfig{Cert: cert, Key: key})
+
+	later := time.Now().Add(time.Minute)
+	if err := os.WriteFile(cert, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(cert, later, later)
+
+	if cn := peerCommonName(t, s.Address()); cn != "first" {
+		t.Fatalf("a broken rotation should keep the previous certificate: got %q", cn)
+	}
+}
+
+func TestTLSRedirectListener(t *testing.T) {
+	cert, key := writeCert(t, t.TempDir(), "first")
+	s := tlsTestServer(t, config.TLSConfig{Cert: cert, Key: key, RedirectAddress: "127.0.0.1:0"})
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get("http://" + s.RedirectAddress() + "/users?page=2")
+	if err != nil {
+		t.Fatalf("GET redirect listener: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPermanentRedirect {
+		t.Fatalf("status: got %d, want 308", resp.StatusCode)
+	}
+	_, port, _ := net.SplitHostPort(s.Address())
+	want := "https://127.0.0.1:" + port + "/users?page=2"
+	if got := resp.Header.Get("Location"); got != want {
+		t.Fatalf("Location: got %q, want %q", got, want)
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	cert, key := writeCert(t, t.TempDir(), "first")
+	certPEM, _ := os.ReadFile(cert)
+	keyPEM, _ := os.ReadFile(key)
+
+	tlsConfig, err := newTLSConfig(config.TLSConfig{
+		Cert:         string(certPEM),
+		Key:          string(keyPEM),
+		MinVersion:   "1.3",
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+		ClientCA:     cert,
+	})
+	if err != nil {
+		t.Fatalf("newTLSConfig: %v", err)
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS13 {
+		t.Fatalf("MinVersion: got %x", tlsConfig.MinVersion)
+	}
+	if len(tlsConfig.CipherSuites) != 1 || tlsConfig.CipherSuites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Fatalf("CipherSuites: got %v", tlsConfig.CipherSuites)
+	}
+	if tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert || tlsConfig.ClientCAs == nil {
+		t.Fatalf("a client CA should require verified client certificates: %v", tlsConfig.ClientAuth)
+	}
+
+	for name, bad := range map[string]config.TLSConfig{
+		"missing key":     {Cert: cert},
+		"min version":     {Cert: cert, Key: key, MinVersion: "1.4"},
+		"cipher suite":    {Cert: cert, Key: key, CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+		"client auth":     {Cert: cert, Key: key, ClientAuth: "sometimes"},
+		"verify needs ca": {Cert: cert, Key: key, ClientAuth: "require_and_verify"},
+		"mixed pem/file":  {Cert: string(certPEM), Key: key},
+	} {
+		if _, err := newTLSConfig(bad); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestListenReportsTLSConfigErrors(t *testing.T) {
+	s := testServer(0)
+	s.tls = config.TLSConfig{Cert: "/nonexistent/cert.pem", Key: "/nonexistent/key.pem"}
+	err := s.Listen()
+	if err == nil {
+		s.Close()
+		t.Fatal("Listen should fail when the certificate cannot be loaded")
+	}
+	if !strings.Contains(err.Error(), "tls") {
+		t.Fatalf("error should mention tls: %v", err)
+	}
+}
@@ -62,3 +62,18 @@ func TestShutdownClosesDatabaseAndRunsHooks(t *testing.T) {
 		t.Fatal("database connector implementing io.Closer should be closed during shutdown")
 	}
 }
+
+func TestCommandLeavesDatabaseClosed(t *testing.T) {
+	t.Setenv(raptor.EnvCmd, raptor.CmdRoutes)
+	conn := &fakeConnector{}
+	raptor.NewTestApp(
+		&raptor.Components{DatabaseConnector: conn},
+		nil,
+		raptor.WithConfig(&config.Config{
+			DatabaseConfig: config.DatabaseConfig{Host: "localhost", Name: "test", AutoMigrate: true},
+		}),
+	)
+	if conn.inited {
+		t.Fatal("a command should not initialize the database connector")
+	}
+}
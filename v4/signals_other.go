@@ -0,0 +1,8 @@
+//go:build !unix
+
+package raptor
+
+import "os"
+
+// Zero-downtime restarts rely on passing listener fds, which is unix-only.
+var restartSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package raptor
+
+import (
+	"os"
+	"syscall"
+)
+
+// restartSignals trigger a zero-downtime restart; see Raptor.Run.
+var restartSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR2}
@@ -1,6 +1,8 @@
 package raptor
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -40,7 +42,7 @@ func NewTestResources() *core.Resources {
 }
 
 func (r *Raptor) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	r.Router.Mux.ServeHTTP(w, req)
+	r.Router.ServeHTTP(w, req)
 }
 
 func (r *Raptor) TestRequest(method, path string, body io.Reader, opts ...TestRequestOption) *httptest.ResponseRecorder {
@@ -62,6 +64,24 @@ func WithHeader(key, value string) TestRequestOption {
 	}
 }
 
+func WithHost(host string) TestRequestOption {
+	return func(req *http.Request) {
+		req.Host = host
+	}
+}
+
+// WithClientCert makes the request arrive over TLS with cert as the
+// verified client certificate, as seen by Context.ClientCert.
+func WithClientCert(cert *x509.Certificate) TestRequestOption {
+	return func(req *http.Request) {
+		req.TLS = &tls.ConnectionState{
+			HandshakeComplete: true,
+			PeerCertificates:  []*x509.Certificate{cert},
+			VerifiedChains:    [][]*x509.Certificate{{cert}},
+		}
+	}
+}
+
 func (r *Raptor) TestGet(path string, opts ...TestRequestOption) *httptest.ResponseRecorder {
 	return r.TestRequest(http.MethodGet, path, nil, opts...)
 }
@@ -0,0 +1,97 @@
+package raptor_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type createThingRequest struct {
+	ID   int    `json:"id" path:"id"`
+	Name string `json:"name"`
+}
+
+type thingResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type TypedController struct {
+	raptor.Controller
+}
+
+func (c *TypedController) Update(ctx *raptor.Context, req createThingRequest) (thingResponse, error) {
+	return thingResponse{ID: req.ID, Name: req.Name}, nil
+}
+
+func (c *TypedController) Remove(ctx *raptor.Context, req *createThingRequest) (*thingResponse, error) {
+	return nil, nil
+}
+
+func (c *TypedController) Wrapped(ctx *raptor.Context) error {
+	return raptor.Handle(func(ctx *raptor.Context, req createThingRequest) (thingResponse, error) {
+		return thingResponse{ID: req.ID, Name: "wrapped"}, nil
+	})(ctx)
+}
+
+func newTypedApp(opts ...raptor.RaptorOption) *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&TypedController{}}},
+		router.CollectRoutes(
+			router.Put("/things/{id}", "Typed.Update"),
+			router.Delete("/things/{id}", "Typed.Remove"),
+			router.Get("/wrapped/{id}", "Typed.Wrapped"),
+		),
+		opts...,
+	)
+}
+
+func TestTypedActionBindsAndRenders(t *testing.T) {
+	app := newTypedApp()
+
+	rec := app.TestPut("/things/7", strings.NewReader(`{"id":99,"name":"rex"}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200 (%s)", rec.Code, rec.Body.String())
+	}
+	var got thingResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got != (thingResponse{ID: 7, Name: "rex"}) {
+		t.Fatalf("path parameters must win over body fields: %+v", got)
+	}
+}
+
+func TestTypedActionNilResultIsNoContent(t *testing.T) {
+	app := newTypedApp()
+
+	if rec := app.TestDelete("/things/7"); rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d, want 204", rec.Code)
+	}
+}
+
+func TestTypedActionValidatesInput(t *testing.T) {
+	app := newTypedApp(raptor.WithValidator(raptor.ValidatorFunc(func(v any) error {
+		if v.(*createThingRequest).Name == "" {
+			return raptor.ValidationErrors{"name": {"is required"}}
+		}
+		return nil
+	})))
+
+	if rec := app.TestPut("/things/7", strings.NewReader(`{}`)); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got %d, want 422", rec.Code)
+	}
+}
+
+func TestHandleWrapsTypedFunction(t *testing.T) {
+	app := newTypedApp()
+
+	rec := app.TestGet("/wrapped/3")
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"id":3,"name":"wrapped"}` {
+		t.Fatalf("got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -19,18 +19,32 @@ type MiddlewareInitializer = core.MiddlewareInitializer
 type Middlewares = core.Middlewares
 type Resources = core.Resources
 type HandlerFunc = core.HandlerFunc
+type Validator = core.Validator
+type ValidatorFunc = core.ValidatorFunc
+type ValidationErrors = core.ValidationErrors
+type ErrorHandler = core.ErrorHandler
+type UUID = core.UUID
+type ClientCert = core.ClientCert
+type ClientCertMiddleware = core.ClientCertMiddleware
 
 var (
-	WrapHandler     = core.WrapHandler
-	WrapHandlerFunc = core.WrapHandlerFunc
-	Use             = core.Use
-	UseOnly         = core.UseOnly
-	UseExcept       = core.UseExcept
-	UseStd          = core.UseStd
-	UseStdOnly      = core.UseStdOnly
-	UseStdExcept    = core.UseStdExcept
+	DefaultErrorHandler = core.DefaultErrorHandler
+	WrapHandler         = core.WrapHandler
+	WrapHandlerFunc     = core.WrapHandlerFunc
+	Use                 = core.Use
+	UseOnly             = core.UseOnly
+	UseExcept           = core.UseExcept
+	UseRouted           = core.UseRouted
+	UseStd              = core.UseStd
+	UseStdOnly          = core.UseStdOnly
+	UseStdExcept        = core.UseStdExcept
 )
 
+// Handle adapts a typed function into a HandlerFunc; see core.Handle.
+func Handle[Req, Res any](fn core.TypedHandlerFunc[Req, Res]) HandlerFunc {
+	return core.Handle(fn)
+}
+
 func WrapMiddleware(mw func(http.Handler) http.Handler) core.ScopedMiddleware {
 	return core.UseStd(mw)
 }
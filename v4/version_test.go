@@ -0,0 +1,82 @@
+package raptor_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-raptor/raptor/v4"
+	"github.com/go-raptor/raptor/v4/config"
+	"github.com/go-raptor/raptor/v4/router"
+)
+
+type VersionedController struct {
+	raptor.Controller
+}
+
+func (c *VersionedController) Show(ctx *raptor.Context) error {
+	return ctx.String(http.StatusOK, ctx.APIVersion()+":"+ctx.Param("id"))
+}
+
+func newVersionedApp(versioning config.VersioningConfig) *raptor.Raptor {
+	return raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&VersionedController{}}},
+		router.CollectRoutes(
+			router.Version("v1", router.Get("/users/{id}", "Versioned.Show")),
+			router.Version("v2", router.Get("/users/{id}", "Versioned.Show")),
+		),
+		raptor.WithConfig(&config.Config{ServerConfig: config.ServerConfig{Versioning: versioning}}),
+	)
+}
+
+func TestVersionDispatch(t *testing.T) {
+	app := newVersionedApp(config.VersioningConfig{Default: "v1", Vendor: "acme"})
+
+	tests := []struct {
+		name string
+		path string
+		opts []raptor.TestRequestOption
+		want string
+	}{
+		{"path prefix", "/v2/users/7", nil, "v2:7"},
+		{"default", "/users/7", nil, "v1:7"},
+		{"header", "/users/7", []raptor.TestRequestOption{raptor.WithHeader("Accept-Version", "2")}, "v2:7"},
+		{"media type", "/users/7", []raptor.TestRequestOption{raptor.WithHeader("Accept", "application/vnd.acme.v2+json")}, "v2:7"},
+	}
+	for _, tt := range tests {
+		rec := app.TestGet(tt.path, tt.opts...)
+		if rec.Code != http.StatusOK || rec.Body.String() != tt.want {
+			t.Fatalf("%s: got %d %q, want %q", tt.name, rec.Code, rec.Body.String(), tt.want)
+		}
+	}
+
+	rec := app.TestGet("/users/7", raptor.WithHeader("Accept-Version", "v9"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown version: got %d, want 404", rec.Code)
+	}
+}
+
+func TestVersionWithoutDefault(t *testing.T) {
+	app := newVersionedApp(config.VersioningConfig{})
+
+	if rec := app.TestGet("/users/7"); rec.Code != http.StatusNotFound {
+		t.Fatalf("unversioned request without a default: got %d, want 404", rec.Code)
+	}
+	if rec := app.TestGet("/v1/users/7"); rec.Body.String() != "v1:7" {
+		t.Fatalf("GET /v1/users/7: got %q", rec.Body.String())
+	}
+}
+
+func TestVersionConflictsWithPlainRoute(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected startup to fail")
+		}
+	}()
+	raptor.NewTestApp(
+		&raptor.Components{Controllers: raptor.Controllers{&VersionedController{}}},
+		router.CollectRoutes(
+			router.Version("v1", router.Get("/users/{id}", "Versioned.Show")),
+			router.Get("/users/{id}", "Versioned.Show"),
+		),
+	)
+}